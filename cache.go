@@ -0,0 +1,98 @@
+package networks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+)
+
+// cacheFileName is the name of the file holding the cached registry inside the cache directory.
+const cacheFileName = "registry.json"
+
+// registryCache is the content of the cache file, the registry document along with when it
+// was fetched.
+type registryCache struct {
+	Version   string    `json:"version"`
+	FetchedAt time.Time `json:"fetchedAt"`
+
+	// Location is where Document was loaded from and verified, empty when it was not. Document
+	// is kept exactly as loaded so it can be verified again.
+	Location string `json:"location,omitempty"`
+	Document string `json:"document"`
+}
+
+// writeRegistryCache writes native to the cache file in dir, creating dir if needed. The file
+// is replaced atomically so a concurrent reader, possibly from another process, never sees a
+// partially written cache.
+//
+// The document native was parsed from is cached as is when its source verified it through
+// [VerifyDocument], native is marshaled otherwise.
+func writeRegistryCache(dir string, native *registry.NetworksRegistry, verified *verifiedDocument, fetchedAt time.Time) error {
+	cache := &registryCache{Version: native.Version, FetchedAt: fetchedAt, Location: verified.location, Document: string(verified.content)}
+	if verified.content == nil {
+		document, err := json.Marshal(native)
+		if err != nil {
+			return fmt.Errorf("marshal registry document: %w", err)
+		}
+
+		cache.Document = string(document)
+	}
+
+	content, err := json.Marshal(cache)
+	if err != nil {
+		return fmt.Errorf("marshal registry cache: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create cache directory: %w", err)
+	}
+
+	file, err := os.CreateTemp(dir, cacheFileName+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temporary cache file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		return fmt.Errorf("write temporary cache file: %w", err)
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("sync temporary cache file: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("close temporary cache file: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), filepath.Join(dir, cacheFileName)); err != nil {
+		return fmt.Errorf("move cache file in place: %w", err)
+	}
+
+	return nil
+}
+
+// readRegistryCache reads back the cache file written by [writeRegistryCache] in dir.
+func readRegistryCache(dir string) (*registryCache, error) {
+	content, err := os.ReadFile(filepath.Join(dir, cacheFileName))
+	if err != nil {
+		return nil, fmt.Errorf("read cache file: %w", err)
+	}
+
+	cache := &registryCache{}
+	if err := json.Unmarshal(content, cache); err != nil {
+		return nil, fmt.Errorf("unmarshal cache file: %w", err)
+	}
+
+	if cache.Document == "" {
+		return nil, fmt.Errorf("cache file has no registry document")
+	}
+
+	return cache, nil
+}
@@ -0,0 +1,102 @@
+package networks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryCache(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested")
+	fetchedAt := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	native := &registry.NetworksRegistry{Version: "0.7.40", Networks: []registry.Network{{ID: "alpha", Aliases: []string{"a"}}}}
+	require.NoError(t, writeRegistryCache(dir, native, &verifiedDocument{}, fetchedAt))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "temporary files must not be left behind")
+	assert.Equal(t, cacheFileName, entries[0].Name())
+
+	cache, err := readRegistryCache(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "0.7.40", cache.Version)
+	assert.Equal(t, fetchedAt, cache.FetchedAt)
+	assert.Empty(t, cache.Location)
+	cached, err := fromJSON([]byte(cache.Document))
+	require.NoError(t, err)
+	assert.Equal(t, native.Networks, cached.Networks)
+
+	t.Run("verified document is kept as is", func(t *testing.T) {
+		document := []byte("{\n  \"version\": \"0.7.40\", \"networks\": []\n}")
+		require.NoError(t, writeRegistryCache(dir, native, &verifiedDocument{location: "https://mirror/registry.json", content: document}, fetchedAt))
+
+		cache, err := readRegistryCache(dir)
+		require.NoError(t, err)
+		assert.Equal(t, "https://mirror/registry.json", cache.Location)
+		assert.Equal(t, string(document), cache.Document)
+	})
+
+	_, err = readRegistryCache(t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestRegistry_WithCacheDir(t *testing.T) {
+	dir := t.TempDir()
+
+	online := New(WithCacheDir(dir), WithSources(staticSource(registry.Network{ID: "recent"})))
+	require.True(t, online.Has("recent"))
+
+	t.Run("restart during outage uses the cache", func(t *testing.T) {
+		offline := New(WithCacheDir(dir), WithSources(failingSource, EmbeddedSource()))
+
+		assert.True(t, offline.Has("recent"))
+		assert.False(t, offline.Has("mainnet"))
+		assert.Equal(t, "cache", offline.snapshot().source)
+	})
+
+	t.Run("stale cache keeps its fetch time", func(t *testing.T) {
+		dir := t.TempDir()
+		fetchedAt := time.Now().Add(-48 * time.Hour).Round(0)
+		require.NoError(t, writeRegistryCache(dir, &registry.NetworksRegistry{Version: "0.0.1", Networks: []registry.Network{{ID: "stale"}}}, &verifiedDocument{}, fetchedAt))
+
+		offline := New(WithCacheDir(dir), WithSources(failingSource, EmbeddedSource()))
+		require.True(t, offline.Has("stale"))
+
+		assert.True(t, fetchedAt.Equal(offline.Status().LoadedAt))
+	})
+
+	t.Run("falls back when the cache is unusable", func(t *testing.T) {
+		offline := New(WithCacheDir(t.TempDir()), WithSources(failingSource, EmbeddedSource()))
+
+		assert.True(t, offline.Has("mainnet"))
+	})
+
+	t.Run("cache is tried last when the chain doesn't end with the embedded source", func(t *testing.T) {
+		r := New(WithCacheDir(dir), WithSources(failingSource, staticSource(registry.Network{ID: "mirror"})))
+
+		assert.Equal(t, []string{"failing", "static", "cache"}, sourceNames(r.sources))
+	})
+
+	t.Run("cache holds the registry without overrides", func(t *testing.T) {
+		cache, err := readRegistryCache(dir)
+		require.NoError(t, err)
+
+		cached, err := fromJSON([]byte(cache.Document))
+		require.NoError(t, err)
+		assert.Equal(t, []registry.Network{{ID: "recent"}}, cached.Networks)
+	})
+}
+
+func sourceNames(sources []Source) (names []string) {
+	for _, source := range sources {
+		names = append(names, source.Name())
+	}
+
+	return names
+}
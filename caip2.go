@@ -0,0 +1,74 @@
+package networks
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+)
+
+var (
+	caip2NamespaceRegex = regexp.MustCompile(`^[-a-z0-9]{3,8}$`)
+	caip2ReferenceRegex = regexp.MustCompile(`^[-_a-zA-Z0-9]{1,32}$`)
+)
+
+// CAIP2 is a CAIP-2 chain ID, like `eip155:1` for Ethereum Mainnet, as found in
+// [registry.Network.Caip2ID]. See https://chainagnostic.org/CAIPs/caip-2.
+type CAIP2 struct {
+	// Namespace is the blockchain ecosystem, like `eip155` or `solana`.
+	Namespace string
+	// Reference identifies the chain within the namespace, like `1`.
+	Reference string
+}
+
+// NewCAIP2 returns the CAIP-2 chain ID made of namespace and reference, checking both are valid.
+func NewCAIP2(namespace, reference string) (CAIP2, error) {
+	id := CAIP2{Namespace: namespace, Reference: reference}
+	if !caip2NamespaceRegex.MatchString(namespace) {
+		return CAIP2{}, fmt.Errorf("invalid CAIP-2 namespace %q, expected 3 to 8 lowercase letters, digits or dashes", namespace)
+	}
+	if !caip2ReferenceRegex.MatchString(reference) {
+		return CAIP2{}, fmt.Errorf("invalid CAIP-2 reference %q, expected 1 to 32 letters, digits, dashes or underscores", reference)
+	}
+
+	return id, nil
+}
+
+// ParseCAIP2 parses a CAIP-2 chain ID formatted as `namespace:reference`.
+func ParseCAIP2(id string) (CAIP2, error) {
+	namespace, reference, found := strings.Cut(id, ":")
+	if !found {
+		return CAIP2{}, fmt.Errorf("invalid CAIP-2 ID %q, expected namespace:reference", id)
+	}
+
+	return NewCAIP2(namespace, reference)
+}
+
+func (c CAIP2) String() string {
+	return c.Namespace + ":" + c.Reference
+}
+
+// FindByCAIP2 returns the network whose [registry.Network.Caip2ID] is id, the one with the
+// lowest ID when several share it, or nil when no network has it.
+func (r NetworkRegistry) FindByCAIP2(id string) *registry.Network {
+	for _, networkID := range slices.Sorted(maps.Keys(r)) {
+		if network := r[networkID]; network.Caip2ID == id {
+			return network
+		}
+	}
+
+	return nil
+}
+
+// FindByCAIP2 is like [NetworkRegistry.FindByCAIP2] on [Registry.Networks].
+func (r *Registry) FindByCAIP2(id string) *registry.Network {
+	return first(r.snapshot().index.byCAIP2[id])
+}
+
+// FindByCAIP2 is a shortcut for [Registry.FindByCAIP2] on the default registry.
+func FindByCAIP2(id string) *registry.Network {
+	return defaultRegistry.FindByCAIP2(id)
+}
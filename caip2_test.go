@@ -0,0 +1,72 @@
+package networks
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCAIP2(t *testing.T) {
+	id, err := ParseCAIP2("eip155:1")
+	require.NoError(t, err)
+	assert.Equal(t, CAIP2{Namespace: "eip155", Reference: "1"}, id)
+	assert.Equal(t, "eip155:1", id.String())
+
+	id, err = ParseCAIP2("acme:dummy-blockchain")
+	require.NoError(t, err)
+	assert.Equal(t, CAIP2{Namespace: "acme", Reference: "dummy-blockchain"}, id)
+
+	tests := []struct {
+		id  string
+		err string
+	}{
+		{"eip155", "expected namespace:reference"},
+		{"ei:1", `invalid CAIP-2 namespace "ei"`},
+		{"EIP155:1", `invalid CAIP-2 namespace "EIP155"`},
+		{"eip155:", `invalid CAIP-2 reference ""`},
+		{"eip155:1:2", `invalid CAIP-2 reference "1:2"`},
+		{"solana:" + string(make([]byte, 33)), "invalid CAIP-2 reference"},
+	}
+
+	for _, test := range tests {
+		_, err := ParseCAIP2(test.id)
+		assert.ErrorContains(t, err, test.err, "ParseCAIP2(%q)", test.id)
+	}
+
+	_, err = NewCAIP2("cosmos", "cosmoshub-4")
+	assert.NoError(t, err)
+}
+
+func TestFindByCAIP2(t *testing.T) {
+	r := loadEmbeddedRegistry(t)
+
+	for _, network := range r.Networks() {
+		_, err := ParseCAIP2(network.Caip2ID)
+		assert.NoError(t, err, "network %q", network.ID)
+	}
+
+	assert.Equal(t, "mainnet", r.FindByCAIP2("eip155:1").ID)
+	assert.Equal(t, "mainnet", r.Networks().FindByCAIP2("eip155:1").ID)
+	assert.Equal(t, "acme-dummy-blockchain", r.FindByCAIP2("acme:dummy-blockchain").ID)
+	assert.Nil(t, r.FindByCAIP2("eip155:0"))
+	assert.Nil(t, r.Networks().FindByCAIP2("eip155:0"))
+
+	assert.Equal(t, "mainnet", r.Find("eip155:1").ID)
+	assert.Equal(t, "mainnet", r.Networks().Find("eip155:1").ID)
+	assert.Contains(t, networkIDs(r.Search(regexp.MustCompile(`^eip155:1$`))), "mainnet")
+
+	network, err := r.Resolve("eip155:1")
+	require.NoError(t, err)
+	assert.Equal(t, "mainnet", network.ID)
+}
+
+func TestNetworkRegistry_FindByCAIP2_Duplicates(t *testing.T) {
+	r := NetworkRegistry{
+		"beta":  {ID: "beta", Caip2ID: "test:1"},
+		"alpha": {ID: "alpha", Caip2ID: "test:1"},
+	}
+
+	assert.Equal(t, "alpha", r.FindByCAIP2("test:1").ID)
+}
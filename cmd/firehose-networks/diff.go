@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+	networks "github.com/streamingfast/firehose-networks"
+)
+
+var diffCommand = &command{
+	name:        "diff",
+	usage:       "diff [-json] <old.json> <new.json>",
+	description: "Prints the network changes between two registry documents",
+	run:         runDiff,
+}
+
+func runDiff(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "Render the diff as JSON instead of text")
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		return fmt.Errorf("expected 2 arguments, the old and new registry files, got %d", flags.NArg())
+	}
+
+	oldRegistry, err := registry.FromFile(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("load old registry %q: %w", flags.Arg(0), err)
+	}
+
+	newRegistry, err := registry.FromFile(flags.Arg(1))
+	if err != nil {
+		return fmt.Errorf("load new registry %q: %w", flags.Arg(1), err)
+	}
+
+	diff := networks.Diff(networks.NewNetworkRegistry(oldRegistry), networks.NewNetworkRegistry(newRegistry))
+	if *asJSON {
+		return diff.WriteJSON(os.Stdout)
+	}
+
+	fmt.Printf("Registry %s -> %s\n", oldRegistry.Version, newRegistry.Version)
+	if diff.IsEmpty() {
+		fmt.Println("No network changes")
+		return nil
+	}
+
+	return diff.WriteText(os.Stdout)
+}
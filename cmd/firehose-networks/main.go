@@ -0,0 +1,90 @@
+// Command firehose-networks inspects network registries as seen by the networks package.
+//
+// Usage:
+//
+//	firehose-networks <command> [flags] [arguments]
+//
+// Run `firehose-networks help` to list the available commands.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	networks "github.com/streamingfast/firehose-networks"
+)
+
+type command struct {
+	name        string
+	usage       string
+	description string
+	run         func(args []string) error
+}
+
+var commands = []*command{
+	diffCommand,
+	originCommand,
+	overridesCommand,
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "help" || os.Args[1] == "-h" || os.Args[1] == "--help" {
+		printUsage()
+		return
+	}
+
+	index := slices.IndexFunc(commands, func(cmd *command) bool { return cmd.name == os.Args[1] })
+	if index == -1 {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+		printUsage()
+		os.Exit(2)
+	}
+
+	if err := commands[index].run(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func printUsage() {
+	var out strings.Builder
+	out.WriteString("Usage: firehose-networks <command> [flags] [arguments]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(&out, "  %-50s %s\n", cmd.usage, cmd.description)
+	}
+
+	fmt.Fprint(os.Stderr, out.String())
+}
+
+// registryFlags defines the flags selecting the registry document and the overrides files of
+// commands inspecting a loaded registry, the returned function loads it once flags are parsed.
+func registryFlags(flags *flag.FlagSet) func() (*networks.Registry, error) {
+	registryFile := flags.String("registry", "", "Load the registry document from this file instead of the default sources")
+	overridesFile := flags.String("overrides", "", "Merge this overrides file, on top of the ones listed in $"+networks.OverridesEnvVar)
+	timeout := flags.Duration("timeout", 10*time.Second, "Maximum time to load the registry")
+
+	return func() (*networks.Registry, error) {
+		var opts []networks.Option
+		if *registryFile != "" {
+			opts = append(opts, networks.WithSources(networks.FileSource(*registryFile)))
+		}
+		if *overridesFile != "" {
+			opts = append(opts, networks.WithOverridesFile(*overridesFile))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		defer cancel()
+
+		reg := networks.New(opts...)
+		if err := reg.Load(ctx); err != nil {
+			return nil, err
+		}
+
+		return reg, nil
+	}
+}
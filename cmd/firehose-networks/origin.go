@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	networks "github.com/streamingfast/firehose-networks"
+)
+
+var originCommand = &command{
+	name:        "origin",
+	usage:       "origin [flags] <network>",
+	description: "Explains where a network and each of its endpoints come from",
+	run:         runOrigin,
+}
+
+func runOrigin(args []string) error {
+	flags := flag.NewFlagSet("origin", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "Render the origin as JSON instead of text")
+	load := registryFlags(flags)
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("expected 1 argument, the network ID or alias, got %d", flags.NArg())
+	}
+
+	reg, err := load()
+	if err != nil {
+		return err
+	}
+
+	network, err := reg.Lookup(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("registry %s: %w", reg.Status().Version, err)
+	}
+
+	origin := reg.Origin(network.ID)
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(origin)
+	}
+
+	out := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(out, "Network %s\t%s\n", origin.NetworkID, origin.Network)
+
+	printEndpoints := func(service string, endpoints []networks.EndpointOrigin) {
+		if len(endpoints) == 0 {
+			return
+		}
+
+		fmt.Fprintf(out, "%s endpoints:\t\n", service)
+		for _, endpoint := range endpoints {
+			fmt.Fprintf(out, "  %s\t%s\n", endpoint.Endpoint, endpoint.Origin)
+		}
+	}
+	printEndpoints("Firehose", origin.Firehose)
+	printEndpoints("Substreams", origin.Substreams)
+
+	if len(origin.Patches) > 0 {
+		fmt.Fprintf(out, "Patched by:\t\n")
+		for _, patch := range origin.Patches {
+			fmt.Fprintf(out, "  %s\t\n", patch)
+		}
+	}
+
+	return out.Flush()
+}
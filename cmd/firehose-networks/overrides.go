@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	networks "github.com/streamingfast/firehose-networks"
+)
+
+var overridesCommand = &command{
+	name:        "overrides",
+	usage:       "overrides [flags]",
+	description: "Reports overrides that are effective, redundant or orphaned",
+	run:         runOverrides,
+}
+
+func runOverrides(args []string) error {
+	flags := flag.NewFlagSet("overrides", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "Render the reports as JSON instead of text")
+	staleOnly := flags.Bool("stale", false, "Only report redundant and orphaned overrides, exiting with an error when there are any")
+	load := registryFlags(flags)
+	flags.Parse(args)
+
+	if flags.NArg() != 0 {
+		return fmt.Errorf("expected no argument, got %d", flags.NArg())
+	}
+
+	reg, err := load()
+	if err != nil {
+		return err
+	}
+
+	reports := reg.OverrideReports()
+	if *staleOnly {
+		stale := []networks.OverrideReport{}
+		for _, report := range reports {
+			if report.Stale() {
+				stale = append(stale, report)
+			}
+		}
+		reports = stale
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(reports); err != nil {
+			return err
+		}
+	} else {
+		out := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(out, "STATUS\tKIND\tNETWORK\tORIGIN\tREDUNDANT ENDPOINTS\n")
+		for _, report := range reports {
+			redundant := strings.Join(slices.Concat(report.RedundantFirehose, report.RedundantSubstreams), ", ")
+			fmt.Fprintf(out, "%s\t%s\t%s\t%s\t%s\n", report.Status, report.Kind, report.NetworkID, report.Origin, redundant)
+		}
+		if err := out.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if *staleOnly && len(reports) > 0 {
+		return fmt.Errorf("%d stale overrides", len(reports))
+	}
+
+	return nil
+}
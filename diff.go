@@ -0,0 +1,315 @@
+package networks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+)
+
+// RegistryDiff lists the networks that differ between two registries, all lists are sorted by
+// network ID. It's computed by [Diff].
+type RegistryDiff struct {
+	// Added are the networks only present in the new registry.
+	Added []*registry.Network
+
+	// Removed are the networks only present in the old registry.
+	Removed []*registry.Network
+
+	// Modified are the networks present in both registries but with different content.
+	Modified []*NetworkDiff
+}
+
+// NetworkDiff is a network present in both registries of a [RegistryDiff] whose content changed.
+type NetworkDiff struct {
+	ID  string
+	Old *registry.Network
+	New *registry.Network
+
+	// Changes are the individual field changes, sorted by field.
+	Changes []*FieldChange
+}
+
+// ChangeKind is the kind of a [FieldChange].
+type ChangeKind string
+
+const (
+	// ChangeAdded is a field, or an element of a list field, only present in the new network.
+	ChangeAdded ChangeKind = "added"
+	// ChangeRemoved is a field, or an element of a list field, only present in the old network.
+	ChangeRemoved ChangeKind = "removed"
+	// ChangeModified is a field present in both networks with a different value.
+	ChangeModified ChangeKind = "modified"
+)
+
+// FieldChange is a single change to a network field. Fields are identified by their JSON path in
+// the registry document, like `services.firehose` or `firehose.firstStreamableBlock`.
+//
+// List fields like `aliases`, `relations` or the `services` endpoints are compared as sets, each
+// element added or removed is its own change. Since the order of a list matters, like for the
+// preferred endpoint, elements present in both lists but reordered are a [ChangeModified] of the
+// list holding the old and new lists. Other fields are compared as a whole, Old and New hold their
+// JSON representation.
+type FieldChange struct {
+	Field string     `json:"field"`
+	Kind  ChangeKind `json:"kind"`
+	Old   string     `json:"old,omitempty"`
+	New   string     `json:"new,omitempty"`
+}
+
+// IsEmpty returns true if both registries hold the exact same networks.
+func (d *RegistryDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// Affects returns true if the network with the given ID was added, removed or modified.
+func (d *RegistryDiff) Affects(networkID string) bool {
+	hasID := func(net *registry.Network) bool { return net.ID == networkID }
+
+	return slices.ContainsFunc(d.Added, hasID) ||
+		slices.ContainsFunc(d.Removed, hasID) ||
+		slices.ContainsFunc(d.Modified, func(net *NetworkDiff) bool { return net.ID == networkID })
+}
+
+// Diff returns the per network changes needed to go from old to new.
+func Diff(old, new NetworkRegistry) *RegistryDiff {
+	ids := slices.Sorted(maps.Keys(old))
+	for id := range new {
+		if _, found := old[id]; !found {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	diff := &RegistryDiff{}
+	for _, id := range ids {
+		oldNet, newNet := old[id], new[id]
+
+		switch {
+		case oldNet == nil:
+			diff.Added = append(diff.Added, newNet)
+		case newNet == nil:
+			diff.Removed = append(diff.Removed, oldNet)
+		case !reflect.DeepEqual(oldNet, newNet):
+			diff.Modified = append(diff.Modified, &NetworkDiff{ID: id, Old: oldNet, New: newNet, Changes: diffNetwork(oldNet, newNet)})
+		}
+	}
+
+	return diff
+}
+
+func diffNetwork(old, new *registry.Network) []*FieldChange {
+	oldFields, newFields := flattenNetwork(old), flattenNetwork(new)
+
+	fields := slices.Sorted(maps.Keys(oldFields))
+	for field := range newFields {
+		if _, found := oldFields[field]; !found {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+
+	var changes []*FieldChange
+	for _, field := range fields {
+		oldValue, inOld := oldFields[field]
+		newValue, inNew := newFields[field]
+
+		oldSet, oldIsSet := oldValue.([]string)
+		newSet, newIsSet := newValue.([]string)
+		if (oldIsSet || !inOld) && (newIsSet || !inNew) {
+			for _, element := range newSet {
+				if !slices.Contains(oldSet, element) {
+					changes = append(changes, &FieldChange{Field: field, Kind: ChangeAdded, New: element})
+				}
+			}
+			for _, element := range oldSet {
+				if !slices.Contains(newSet, element) {
+					changes = append(changes, &FieldChange{Field: field, Kind: ChangeRemoved, Old: element})
+				}
+			}
+			if !slices.Equal(keptElements(oldSet, newSet), keptElements(newSet, oldSet)) {
+				changes = append(changes, &FieldChange{Field: field, Kind: ChangeModified, Old: mustMarshalString(oldSet), New: mustMarshalString(newSet)})
+			}
+			continue
+		}
+
+		oldScalar, newScalar := fmt.Sprint(oldValue), fmt.Sprint(newValue)
+		switch {
+		case !inOld:
+			changes = append(changes, &FieldChange{Field: field, Kind: ChangeAdded, New: newScalar})
+		case !inNew:
+			changes = append(changes, &FieldChange{Field: field, Kind: ChangeRemoved, Old: oldScalar})
+		case oldScalar != newScalar:
+			changes = append(changes, &FieldChange{Field: field, Kind: ChangeModified, Old: oldScalar, New: newScalar})
+		}
+	}
+
+	return changes
+}
+
+// keptElements returns the elements of list also present in other, in the order of list.
+func keptElements(list, other []string) []string {
+	return slices.DeleteFunc(slices.Clone(list), func(element string) bool { return !slices.Contains(other, element) })
+}
+
+// flattenNetwork maps each field path of the network JSON representation to its value, either a
+// []string for list of strings compared as sets or the field's JSON otherwise.
+func flattenNetwork(network *registry.Network) map[string]any {
+	content, err := json.Marshal(network)
+	if err != nil {
+		panic(fmt.Errorf("network %q should always be marshallable: %w", network.ID, err))
+	}
+
+	var object map[string]any
+	if err := json.Unmarshal(content, &object); err != nil {
+		panic(fmt.Errorf("network %q should always be unmarshallable: %w", network.ID, err))
+	}
+
+	fields := map[string]any{}
+	flattenInto(fields, "", object)
+
+	// Relations are a list of objects, they are more readable compared as a set of `kind:network`
+	if relations := network.Relations; len(relations) > 0 {
+		set := make([]string, len(relations))
+		for i, relation := range relations {
+			set[i] = string(relation.Kind) + ":" + relation.Network
+		}
+		fields["relations"] = set
+	}
+
+	// First streamable block is only meaningful as a whole, not per height and ID
+	if network.Firehose != nil && network.Firehose.FirstStreamableBlock != nil {
+		block := network.Firehose.FirstStreamableBlock
+		delete(fields, "firehose.firstStreamableBlock.height")
+		delete(fields, "firehose.firstStreamableBlock.id")
+		fields["firehose.firstStreamableBlock"] = fmt.Sprintf("#%d (%s)", block.Height, block.ID)
+	}
+
+	return fields
+}
+
+func flattenInto(fields map[string]any, prefix string, value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+
+			flattenInto(fields, path, child)
+		}
+
+	case []any:
+		set := make([]string, 0, len(v))
+		for _, element := range v {
+			str, ok := element.(string)
+			if !ok {
+				fields[prefix] = mustMarshalString(v)
+				return
+			}
+
+			set = append(set, str)
+		}
+
+		fields[prefix] = set
+
+	case string:
+		fields[prefix] = v
+
+	default:
+		fields[prefix] = mustMarshalString(v)
+	}
+}
+
+func mustMarshalString(v any) string {
+	content, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Errorf("value %v should always be marshallable: %w", v, err))
+	}
+
+	return string(content)
+}
+
+// WriteText renders the diff in a human readable format, one line per added or removed network
+// and one line per field change of modified networks.
+func (d *RegistryDiff) WriteText(w io.Writer) error {
+	var out strings.Builder
+	for _, net := range d.Added {
+		fmt.Fprintf(&out, "+ %s (%s)\n", net.ID, net.FullName)
+	}
+
+	for _, net := range d.Removed {
+		fmt.Fprintf(&out, "- %s (%s)\n", net.ID, net.FullName)
+	}
+
+	for _, net := range d.Modified {
+		fmt.Fprintf(&out, "~ %s (%s)\n", net.ID, net.New.FullName)
+
+		for _, change := range net.Changes {
+			switch change.Kind {
+			case ChangeAdded:
+				fmt.Fprintf(&out, "    %s: + %s\n", change.Field, change.New)
+			case ChangeRemoved:
+				fmt.Fprintf(&out, "    %s: - %s\n", change.Field, change.Old)
+			case ChangeModified:
+				fmt.Fprintf(&out, "    %s: %s -> %s\n", change.Field, change.Old, change.New)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// String returns the text rendering of the diff, see [RegistryDiff.WriteText].
+func (d *RegistryDiff) String() string {
+	var out bytes.Buffer
+	_ = d.WriteText(&out)
+
+	return out.String()
+}
+
+// WriteJSON renders the diff as indented JSON, see [RegistryDiff.MarshalJSON] for the format.
+func (d *RegistryDiff) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(d)
+}
+
+type jsonRegistryDiff struct {
+	Added    []string           `json:"added"`
+	Removed  []string           `json:"removed"`
+	Modified []*jsonNetworkDiff `json:"modified"`
+}
+
+type jsonNetworkDiff struct {
+	ID      string         `json:"id"`
+	Changes []*FieldChange `json:"changes"`
+}
+
+// MarshalJSON renders added and removed networks as lists of network IDs and modified networks
+// as objects holding the network ID and its field changes.
+func (d *RegistryDiff) MarshalJSON() ([]byte, error) {
+	out := jsonRegistryDiff{Added: []string{}, Removed: []string{}, Modified: []*jsonNetworkDiff{}}
+	for _, net := range d.Added {
+		out.Added = append(out.Added, net.ID)
+	}
+
+	for _, net := range d.Removed {
+		out.Removed = append(out.Removed, net.ID)
+	}
+
+	for _, net := range d.Modified {
+		out.Modified = append(out.Modified, &jsonNetworkDiff{ID: net.ID, Changes: net.Changes})
+	}
+
+	return json.Marshal(out)
+}
@@ -0,0 +1,145 @@
+package networks
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	deprecatedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	oldMainnet := &registry.Network{
+		ID:       "mainnet",
+		FullName: "Ethereum Mainnet",
+		Aliases:  []string{"eth", "ethereum"},
+		Services: registry.Services{
+			Firehose:   []string{"mainnet.eth.streamingfast.io:443", "eth.firehose.pinax.network:443"},
+			Substreams: []string{"mainnet.eth.streamingfast.io:443"},
+		},
+		Firehose: &registry.Firehose{
+			BlockType:            "sf.ethereum.type.v2.Block",
+			BytesEncoding:        registry.Hex,
+			FirstStreamableBlock: &registry.FirstStreamableBlock{Height: 0, ID: "0xaa"},
+		},
+		Relations: []registry.Relation{{Kind: registry.L2Of, Network: "other"}},
+	}
+	newMainnet := &registry.Network{
+		ID:       "mainnet",
+		FullName: "Ethereum Mainnet",
+		Aliases:  []string{"eth", "evm-1"},
+		Services: registry.Services{
+			Firehose:   []string{"eth.firehose.pinax.network:443", "eth.firehose.data.nexus:443"},
+			Substreams: []string{"mainnet.eth.streamingfast.io:443"},
+		},
+		Firehose: &registry.Firehose{
+			BlockType:            "sf.ethereum.type.v3.Block",
+			BytesEncoding:        registry.The0Xhex,
+			FirstStreamableBlock: &registry.FirstStreamableBlock{Height: 1, ID: "0xbb"},
+			DeprecatedAt:         &deprecatedAt,
+		},
+		Relations: []registry.Relation{{Kind: registry.ForkedFrom, Network: "other"}},
+	}
+
+	old := NetworkRegistry{
+		"mainnet": oldMainnet,
+		"removed": {ID: "removed", FullName: "Removed Chain"},
+		"same":    {ID: "same", Aliases: []string{"unchanged"}},
+	}
+	new := NetworkRegistry{
+		"mainnet": newMainnet,
+		"added":   {ID: "added", FullName: "Added Chain"},
+		"same":    {ID: "same", Aliases: []string{"unchanged"}},
+	}
+
+	diff := Diff(old, new)
+	require.Len(t, diff.Added, 1)
+	assert.Equal(t, "added", diff.Added[0].ID)
+	require.Len(t, diff.Removed, 1)
+	assert.Equal(t, "removed", diff.Removed[0].ID)
+	require.Len(t, diff.Modified, 1)
+
+	modified := diff.Modified[0]
+	assert.Equal(t, "mainnet", modified.ID)
+	assert.Same(t, oldMainnet, modified.Old)
+	assert.Same(t, newMainnet, modified.New)
+	assert.Equal(t, []*FieldChange{
+		{Field: "aliases", Kind: ChangeAdded, New: "evm-1"},
+		{Field: "aliases", Kind: ChangeRemoved, Old: "ethereum"},
+		{Field: "firehose.blockType", Kind: ChangeModified, Old: "sf.ethereum.type.v2.Block", New: "sf.ethereum.type.v3.Block"},
+		{Field: "firehose.bytesEncoding", Kind: ChangeModified, Old: "hex", New: "0xhex"},
+		{Field: "firehose.deprecatedAt", Kind: ChangeAdded, New: "2025-01-01T00:00:00Z"},
+		{Field: "firehose.firstStreamableBlock", Kind: ChangeModified, Old: "#0 (0xaa)", New: "#1 (0xbb)"},
+		{Field: "relations", Kind: ChangeAdded, New: "forkedFrom:other"},
+		{Field: "relations", Kind: ChangeRemoved, Old: "l2Of:other"},
+		{Field: "services.firehose", Kind: ChangeAdded, New: "eth.firehose.data.nexus:443"},
+		{Field: "services.firehose", Kind: ChangeRemoved, Old: "mainnet.eth.streamingfast.io:443"},
+	}, modified.Changes)
+
+	assert.True(t, diff.Affects("mainnet"))
+	assert.True(t, diff.Affects("added"))
+	assert.True(t, diff.Affects("removed"))
+	assert.False(t, diff.Affects("same"))
+
+	assert.True(t, Diff(old, old).IsEmpty())
+}
+
+func TestDiff_Reordered(t *testing.T) {
+	diff := Diff(
+		NetworkRegistry{"mainnet": {ID: "mainnet", Services: registry.Services{Firehose: []string{"x:443", "y:443"}, Substreams: []string{"a:443", "b:443"}}}},
+		NetworkRegistry{"mainnet": {ID: "mainnet", Services: registry.Services{Firehose: []string{"y:443", "x:443"}, Substreams: []string{"a:443", "c:443", "b:443"}}}},
+	)
+
+	require.Len(t, diff.Modified, 1)
+	assert.Equal(t, []*FieldChange{
+		{Field: "services.firehose", Kind: ChangeModified, Old: `["x:443","y:443"]`, New: `["y:443","x:443"]`},
+		{Field: "services.substreams", Kind: ChangeAdded, New: "c:443"},
+	}, diff.Modified[0].Changes)
+}
+
+func TestRegistryDiff_Renderers(t *testing.T) {
+	diff := Diff(
+		NetworkRegistry{
+			"alpha": {ID: "alpha", FullName: "Alpha", Services: registry.Services{Firehose: []string{"a:443"}}},
+			"beta":  {ID: "beta", FullName: "Beta"},
+		},
+		NetworkRegistry{
+			"alpha": {ID: "alpha", FullName: "Alpha", Services: registry.Services{Firehose: []string{"b:443"}}},
+			"gamma": {ID: "gamma", FullName: "Gamma"},
+		},
+	)
+
+	t.Run("text", func(t *testing.T) {
+		assert.Equal(t, `+ gamma (Gamma)
+- beta (Beta)
+~ alpha (Alpha)
+    services.firehose: + b:443
+    services.firehose: - a:443
+`, diff.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, diff.WriteJSON(&out))
+
+		assert.JSONEq(t, `{
+			"added": ["gamma"],
+			"removed": ["beta"],
+			"modified": [{"id": "alpha", "changes": [
+				{"field": "services.firehose", "kind": "added", "new": "b:443"},
+				{"field": "services.firehose", "kind": "removed", "old": "a:443"}
+			]}]
+		}`, out.String())
+	})
+
+	t.Run("empty json", func(t *testing.T) {
+		content, err := json.Marshal(Diff(NetworkRegistry{}, NetworkRegistry{}))
+		require.NoError(t, err)
+
+		assert.JSONEq(t, `{"added": [], "removed": [], "modified": []}`, string(content))
+	})
+}
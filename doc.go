@@ -57,12 +57,57 @@
 //	// Find by Substreams endpoint
 //	network = registry.FindBySubstreamsEndpoint("https://mainnet.eth.streamingfast.io:443")
 //
+// # Registry Instances
+//
+// The package level functions above all operate on a default [Registry] returned by [Default].
+// A dedicated instance can be created with [New] when a different configuration is needed, for
+// example in tests or when a process needs to hold several registries at once:
+//
+//	reg := networks.New(
+//	    networks.WithSources(networks.FileSource("registry.json")),
+//	    networks.WithNetworks(myDevnet),
+//	)
+//
+//	network := reg.Find("my-devnet")
+//
 // # Fallback Mechanism
 //
 // The package automatically handles network registry availability. If the remote
 // registry cannot be loaded, it falls back to an embedded JSON file and launches
 // a background process to retry loading the latest registry with exponential backoff.
 //
+// The registry loads implicitly on first lookup, bounded by [WithLoadTimeout]. Call [Load]
+// beforehand to control how long loading can take and to handle failures:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+//	defer cancel()
+//
+//	if err := networks.Load(ctx); err != nil {
+//	    // *networks.LoadError listing why each source failed
+//	}
+//
+// Long-running services can be notified when a refresh changes the registry, for example to
+// reconnect when the endpoints of their network change:
+//
+//	networks.ScheduleUpdateLatestRegistry(ctx, 15*time.Minute, logger)
+//	networks.Subscribe(func(update networks.Update) {
+//	    if update.Diff.Affects("mainnet") {
+//	        // Reconnect using networks.GetFirehoseEndpoint("mainnet")
+//	    }
+//	})
+//
+// # Sources
+//
+// The registry document is loaded from an ordered chain of [Source], the first one loading
+// successfully is used. The default chain is [LatestSource] then [EmbeddedSource], air-gapped
+// deployments can point to an internal mirror instead:
+//
+//	reg := networks.New(networks.WithSources(
+//	    networks.URLSource("https://mirror.internal/TheGraphNetworksRegistry.json"),
+//	    networks.EnvFileSource("NETWORKS_REGISTRY_FILE"),
+//	    networks.EmbeddedSource(),
+//	))
+//
 // # Custom Networks
 //
 // The package supports custom network overrides for development and testing purposes.
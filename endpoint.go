@@ -0,0 +1,103 @@
+package networks
+
+import (
+	"maps"
+	"net"
+	"slices"
+	"strings"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+)
+
+// NormalizeEndpoint returns endpoint as `host:port` so that the different ways of writing the
+// same endpoint compare equal: the scheme, including the `dns:///` of gRPC targets, and the path
+// are dropped, the host is lowercased and the port defaults to 443, or 80 for `http://`.
+//
+// For example `https://Mainnet.eth.streamingfast.io/`, `mainnet.eth.streamingfast.io:443` and
+// `dns:///mainnet.eth.streamingfast.io:443` all become `mainnet.eth.streamingfast.io:443`.
+func NormalizeEndpoint(endpoint string) string {
+	endpoint = strings.TrimSpace(endpoint)
+
+	port := "443"
+	if scheme, rest, found := strings.Cut(endpoint, "://"); found {
+		endpoint = rest
+
+		switch strings.ToLower(scheme) {
+		case "http":
+			port = "80"
+		case "dns":
+			// The target of a dns URI comes after its optional DNS server authority.
+			_, endpoint, _ = strings.Cut(rest, "/")
+		}
+	}
+
+	endpoint, _, _ = strings.Cut(endpoint, "/")
+	if endpoint == "" {
+		return ""
+	}
+
+	host, explicitPort, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		host = strings.Trim(endpoint, "[]")
+	} else {
+		port = explicitPort
+	}
+
+	return net.JoinHostPort(strings.ToLower(host), port)
+}
+
+// FindByFirehoseEndpoint returns the *registry.Network whose Firehose endpoint matches the given
+// endpoint, the network with the lowest ID when several match.
+func (r NetworkRegistry) FindByFirehoseEndpoint(endpoint string) *registry.Network {
+	for _, id := range slices.Sorted(maps.Keys(r)) {
+		if network := r[id]; slices.Contains(network.Services.Firehose, endpoint) {
+			return network
+		}
+	}
+
+	return nil
+}
+
+// FindByEndpoint returns every network having endpoint among its Firehose or Substreams
+// endpoints, sorted by ID, both sides being compared once normalized by [NormalizeEndpoint].
+// Several networks are returned when they share an endpoint.
+func (r NetworkRegistry) FindByEndpoint(endpoint string) []*registry.Network {
+	normalized := NormalizeEndpoint(endpoint)
+	if normalized == "" {
+		return nil
+	}
+
+	var out []*registry.Network
+	for _, id := range slices.Sorted(maps.Keys(r)) {
+		network := r[id]
+		if slices.ContainsFunc(slices.Concat(network.Services.Firehose, network.Services.Substreams), func(candidate string) bool {
+			return NormalizeEndpoint(candidate) == normalized
+		}) {
+			out = append(out, network)
+		}
+	}
+
+	return out
+}
+
+// FindByFirehoseEndpoint is like [NetworkRegistry.FindByFirehoseEndpoint] on
+// [Registry.Networks], the network with the lowest ID being returned when several match.
+func (r *Registry) FindByFirehoseEndpoint(endpoint string) *registry.Network {
+	return first(r.snapshot().index.byFirehoseEndpoint[endpoint])
+}
+
+// FindByEndpoint is like [NetworkRegistry.FindByEndpoint] on [Registry.Networks].
+func (r *Registry) FindByEndpoint(endpoint string) []*registry.Network {
+	return slices.Clone(r.snapshot().index.byEndpoint[NormalizeEndpoint(endpoint)])
+}
+
+// FindByFirehoseEndpoint is a shortcut for [Registry.FindByFirehoseEndpoint] on the default
+// registry.
+func FindByFirehoseEndpoint(endpoint string) *registry.Network {
+	return defaultRegistry.FindByFirehoseEndpoint(endpoint)
+}
+
+// FindByEndpoint is a shortcut for [Registry.FindByEndpoint] on the default registry.
+func FindByEndpoint(endpoint string) []*registry.Network {
+	return defaultRegistry.FindByEndpoint(endpoint)
+}
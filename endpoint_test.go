@@ -0,0 +1,69 @@
+package networks
+
+import (
+	"testing"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeEndpoint(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		expected string
+	}{
+		{"mainnet.eth.streamingfast.io:443", "mainnet.eth.streamingfast.io:443"},
+		{"https://mainnet.eth.streamingfast.io", "mainnet.eth.streamingfast.io:443"},
+		{"https://Mainnet.ETH.streamingfast.io/", "mainnet.eth.streamingfast.io:443"},
+		{"dns:///mainnet.eth.streamingfast.io:443", "mainnet.eth.streamingfast.io:443"},
+		{"dns://8.8.8.8/mainnet.eth.streamingfast.io:443", "mainnet.eth.streamingfast.io:443"},
+		{"grpcs://mainnet.eth.streamingfast.io:443/sf.firehose.v2.Stream", "mainnet.eth.streamingfast.io:443"},
+		{" mainnet.eth.streamingfast.io ", "mainnet.eth.streamingfast.io:443"},
+		{"http://localhost", "localhost:80"},
+		{"localhost:10015", "localhost:10015"},
+		{"[::1]:9000", "[::1]:9000"},
+		{"[::1]", "[::1]:443"},
+		{"https://", ""},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, NormalizeEndpoint(test.endpoint), "NormalizeEndpoint(%q)", test.endpoint)
+	}
+}
+
+func TestRegistry_FindByEndpoint(t *testing.T) {
+	r := New(WithSources(staticSource(
+		registry.Network{ID: "mainnet", Services: registry.Services{
+			Firehose:   []string{"mainnet.eth.streamingfast.io:443", "eth.firehose.pinax.network:443"},
+			Substreams: []string{"mainnet.eth.streamingfast.io:443"},
+		}},
+		registry.Network{ID: "bsc", Services: registry.Services{Substreams: []string{"multi.chain.io:443"}}},
+		registry.Network{ID: "base", Services: registry.Services{Firehose: []string{"multi.chain.io:443"}}},
+	)))
+
+	assert.Equal(t, "mainnet", r.FindByFirehoseEndpoint("eth.firehose.pinax.network:443").ID)
+	assert.Equal(t, "mainnet", r.Networks().FindByFirehoseEndpoint("eth.firehose.pinax.network:443").ID)
+	assert.Nil(t, r.FindByFirehoseEndpoint("https://eth.firehose.pinax.network"), "exact match only")
+	assert.Nil(t, r.FindBySubstreamsEndpoint("eth.firehose.pinax.network:443"))
+
+	shared := NetworkRegistry{
+		"sepolia": {ID: "sepolia", Services: registry.Services{Firehose: []string{"shared.io:443"}, Substreams: []string{"shared.io:443"}}},
+		"holesky": {ID: "holesky", Services: registry.Services{Firehose: []string{"shared.io:443"}, Substreams: []string{"shared.io:443"}}},
+		"hoodi":   {ID: "hoodi", Services: registry.Services{Firehose: []string{"shared.io:443"}, Substreams: []string{"shared.io:443"}}},
+	}
+	for range 20 {
+		assert.Equal(t, "holesky", shared.FindByFirehoseEndpoint("shared.io:443").ID, "lowest ID wins")
+		assert.Equal(t, "holesky", shared.FindBySubstreamsEndpoint("shared.io:443").ID, "lowest ID wins")
+	}
+
+	for _, endpoint := range []string{"https://mainnet.eth.streamingfast.io", "mainnet.eth.streamingfast.io:443", "dns:///mainnet.eth.streamingfast.io:443"} {
+		assert.Equal(t, []string{"mainnet"}, networkIDs(r.FindByEndpoint(endpoint)), "FindByEndpoint(%q)", endpoint)
+		assert.Equal(t, []string{"mainnet"}, networkIDs(r.Networks().FindByEndpoint(endpoint)), "NetworkRegistry.FindByEndpoint(%q)", endpoint)
+	}
+
+	assert.Equal(t, []string{"base", "bsc"}, networkIDs(r.FindByEndpoint("https://multi.chain.io/")))
+	assert.Equal(t, []string{"base", "bsc"}, networkIDs(r.Networks().FindByEndpoint("https://multi.chain.io/")))
+	assert.Nil(t, r.FindByEndpoint("unknown.io:443"))
+	assert.Nil(t, r.FindByEndpoint(""))
+}
@@ -0,0 +1,51 @@
+package networks
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LoadError is returned when none of the sources of a [Registry] could be loaded, it holds
+// the failure of each source tried, in order.
+type LoadError struct {
+	Failures []*SourceError
+}
+
+func (e *LoadError) Error() string {
+	if len(e.Failures) == 0 {
+		return "load registry: no source configured"
+	}
+
+	failures := make([]string, len(e.Failures))
+	for i, failure := range e.Failures {
+		failures[i] = failure.Error()
+	}
+
+	return "load registry: all sources failed: " + strings.Join(failures, "; ")
+}
+
+// Unwrap returns the failure of each source so [errors.Is] and [errors.As] can inspect them,
+// for example to check for [context.DeadlineExceeded].
+func (e *LoadError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, failure := range e.Failures {
+		errs[i] = failure
+	}
+
+	return errs
+}
+
+// SourceError is the failure of a single [Source] to load.
+type SourceError struct {
+	// Source is the [Source.Name] of the failed source.
+	Source string
+	Err    error
+}
+
+func (e *SourceError) Error() string {
+	return fmt.Sprintf("source %s: %s", e.Source, e.Err)
+}
+
+func (e *SourceError) Unwrap() error {
+	return e.Err
+}
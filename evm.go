@@ -0,0 +1,61 @@
+package networks
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+)
+
+// EVMNamespace is the CAIP-2 namespace of EVM chains, whose reference is the chain ID.
+const EVMNamespace = "eip155"
+
+// EVMChainID returns the chain ID of network, as returned by the `eth_chainId` RPC call, and
+// false when network is not an EVM chain. It comes from the `eip155:<chain ID>` CAIP-2 ID of
+// network or, when it has none, from an `evm-<chain ID>` alias.
+func EVMChainID(network *registry.Network) (uint64, bool) {
+	if network == nil {
+		return 0, false
+	}
+
+	if id, err := ParseCAIP2(network.Caip2ID); err == nil && id.Namespace == EVMNamespace {
+		if chainID, err := strconv.ParseUint(id.Reference, 10, 64); err == nil {
+			return chainID, true
+		}
+	}
+
+	for _, alias := range network.Aliases {
+		if reference, found := strings.CutPrefix(alias, "evm-"); found {
+			if chainID, err := strconv.ParseUint(reference, 10, 64); err == nil {
+				return chainID, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// FindByEVMChainID returns the network whose [EVMChainID] is chainID, the one with the lowest ID
+// when several share it, or nil when no network has it.
+func (r NetworkRegistry) FindByEVMChainID(chainID uint64) *registry.Network {
+	for _, id := range slices.Sorted(maps.Keys(r)) {
+		if networkChainID, ok := EVMChainID(r[id]); ok && networkChainID == chainID {
+			return r[id]
+		}
+	}
+
+	return nil
+}
+
+// FindByEVMChainID is like [NetworkRegistry.FindByEVMChainID] on [Registry.Networks]. It lets a
+// node identify its network from the chain ID returned by the `eth_chainId` RPC call.
+func (r *Registry) FindByEVMChainID(chainID uint64) *registry.Network {
+	return first(r.snapshot().index.byEVMChainID[chainID])
+}
+
+// FindByEVMChainID is a shortcut for [Registry.FindByEVMChainID] on the default registry.
+func FindByEVMChainID(chainID uint64) *registry.Network {
+	return defaultRegistry.FindByEVMChainID(chainID)
+}
@@ -0,0 +1,51 @@
+package networks
+
+import (
+	"testing"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEVMChainID(t *testing.T) {
+	tests := []struct {
+		name     string
+		network  *registry.Network
+		expected uint64
+		ok       bool
+	}{
+		{"caip2", &registry.Network{Caip2ID: "eip155:42161"}, 42161, true},
+		{"caip2 wins over alias", &registry.Network{Caip2ID: "eip155:1", Aliases: []string{"evm-2"}}, 1, true},
+		{"alias", &registry.Network{Caip2ID: "custom:chain", Aliases: []string{"chain", "evm-1337"}}, 1337, true},
+		{"beacon", &registry.Network{Caip2ID: "beacon:1"}, 0, false},
+		{"invalid reference", &registry.Network{Caip2ID: "eip155:mainnet", Aliases: []string{"evm-mainnet"}}, 0, false},
+		{"nil", nil, 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chainID, ok := EVMChainID(test.network)
+			assert.Equal(t, test.expected, chainID)
+			assert.Equal(t, test.ok, ok)
+		})
+	}
+}
+
+func TestFindByEVMChainID(t *testing.T) {
+	r := loadEmbeddedRegistry(t)
+
+	assert.Equal(t, "mainnet", r.FindByEVMChainID(1).ID)
+	assert.Equal(t, "arbitrum-one", r.FindByEVMChainID(42161).ID)
+	assert.Equal(t, "sepolia", r.FindByEVMChainID(11155111).ID)
+	assert.Nil(t, r.FindByEVMChainID(0))
+
+	for _, network := range r.Networks() {
+		chainID, ok := EVMChainID(network)
+		if !ok {
+			continue
+		}
+
+		assert.Same(t, network, r.FindByEVMChainID(chainID), "chain ID %d of %q", chainID, network.ID)
+		assert.Same(t, network, r.Networks().FindByEVMChainID(chainID), "chain ID %d of %q", chainID, network.ID)
+	}
+}
@@ -0,0 +1,79 @@
+package networks
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+)
+
+// blockTypeURLPrefix is the prefix of the type URL of blocks packed in a protobuf Any, like in
+// Firehose responses.
+const blockTypeURLPrefix = "type.googleapis.com/"
+
+// FilterByBlockType returns the networks whose [registry.Firehose.BlockType] is blockType, like
+// `sf.ethereum.type.v2.Block`, sorted by ID. blockType can also be the type URL of a protobuf
+// Any, like `type.googleapis.com/sf.ethereum.type.v2.Block`.
+func (r NetworkRegistry) FilterByBlockType(blockType string) []*registry.Network {
+	blockType = strings.TrimPrefix(blockType, blockTypeURLPrefix)
+	return r.filterSorted(func(network *registry.Network) bool {
+		return network.Firehose != nil && network.Firehose.BlockType == blockType
+	})
+}
+
+// FilterByProtocol returns the networks whose [registry.GraphNode.Protocol] is protocol, sorted
+// by ID.
+func (r NetworkRegistry) FilterByProtocol(protocol registry.Protocol) []*registry.Network {
+	return r.filterSorted(func(network *registry.Network) bool {
+		return network.GraphNode != nil && network.GraphNode.Protocol != nil && *network.GraphNode.Protocol == protocol
+	})
+}
+
+// GroupByBlockType groups the networks by [registry.Firehose.BlockType], each group being sorted
+// by ID. Networks without a block type are left out.
+func (r NetworkRegistry) GroupByBlockType() map[string][]*registry.Network {
+	groups := make(map[string][]*registry.Network)
+	for _, network := range r.sorted() {
+		if network.Firehose != nil && network.Firehose.BlockType != "" {
+			groups[network.Firehose.BlockType] = append(groups[network.Firehose.BlockType], network)
+		}
+	}
+
+	return groups
+}
+
+// GroupByProtocol groups the networks by [registry.GraphNode.Protocol], each group being sorted
+// by ID. Networks without a protocol are left out.
+func (r NetworkRegistry) GroupByProtocol() map[registry.Protocol][]*registry.Network {
+	groups := make(map[registry.Protocol][]*registry.Network)
+	for _, network := range r.sorted() {
+		if network.GraphNode != nil && network.GraphNode.Protocol != nil {
+			protocol := *network.GraphNode.Protocol
+			groups[protocol] = append(groups[protocol], network)
+		}
+	}
+
+	return groups
+}
+
+// sorted returns the networks sorted by ID.
+func (r NetworkRegistry) sorted() []*registry.Network {
+	networks := make([]*registry.Network, 0, len(r))
+	for _, id := range slices.Sorted(maps.Keys(r)) {
+		networks = append(networks, r[id])
+	}
+
+	return networks
+}
+
+func (r NetworkRegistry) filterSorted(shouldInclude func(*registry.Network) bool) []*registry.Network {
+	var networks []*registry.Network
+	for _, network := range r.sorted() {
+		if shouldInclude(network) {
+			networks = append(networks, network)
+		}
+	}
+
+	return networks
+}
@@ -0,0 +1,47 @@
+package networks
+
+import (
+	"testing"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkRegistry_FilterAndGroup(t *testing.T) {
+	ethereum, near := registry.Ethereum, registry.Near
+	r := NetworkRegistry{
+		"sepolia": {ID: "sepolia", Firehose: &registry.Firehose{BlockType: "sf.ethereum.type.v2.Block"}, GraphNode: &registry.GraphNode{Protocol: &ethereum}},
+		"mainnet": {ID: "mainnet", Firehose: &registry.Firehose{BlockType: "sf.ethereum.type.v2.Block"}, GraphNode: &registry.GraphNode{Protocol: &ethereum}},
+		"near":    {ID: "near", Firehose: &registry.Firehose{BlockType: "sf.near.type.v1.Block"}, GraphNode: &registry.GraphNode{Protocol: &near}},
+		"bitcoin": {ID: "bitcoin", Firehose: &registry.Firehose{BlockType: "sf.bitcoin.type.v1.Block"}},
+		"custom":  {ID: "custom"},
+	}
+
+	assert.Equal(t, []string{"mainnet", "sepolia"}, networkIDs(r.FilterByBlockType("sf.ethereum.type.v2.Block")))
+	assert.Equal(t, []string{"mainnet", "sepolia"}, networkIDs(r.FilterByBlockType("type.googleapis.com/sf.ethereum.type.v2.Block")))
+	assert.Nil(t, r.FilterByBlockType("sf.unknown.type.v1.Block"))
+
+	assert.Equal(t, []string{"mainnet", "sepolia"}, networkIDs(r.FilterByProtocol(registry.Ethereum)))
+	assert.Equal(t, []string{"near"}, networkIDs(r.FilterByProtocol(registry.Near)))
+	assert.Nil(t, r.FilterByProtocol(registry.Cosmos))
+
+	blockTypes := r.GroupByBlockType()
+	assert.Len(t, blockTypes, 3)
+	assert.Equal(t, []string{"mainnet", "sepolia"}, networkIDs(blockTypes["sf.ethereum.type.v2.Block"]))
+	assert.Equal(t, []string{"bitcoin"}, networkIDs(blockTypes["sf.bitcoin.type.v1.Block"]))
+
+	protocols := r.GroupByProtocol()
+	assert.Len(t, protocols, 2)
+	assert.Equal(t, []string{"mainnet", "sepolia"}, networkIDs(protocols[registry.Ethereum]))
+	assert.Equal(t, []string{"near"}, networkIDs(protocols[registry.Near]))
+}
+
+func TestNetworkRegistry_FilterAndGroup_Embedded(t *testing.T) {
+	networks := loadEmbeddedRegistry(t).Networks()
+
+	cosmos := networks.FilterByBlockType("sf.cosmos.type.v2.Block")
+	assert.NotEmpty(t, cosmos)
+	assert.Equal(t, cosmos, networks.GroupByBlockType()["sf.cosmos.type.v2.Block"])
+	assert.Contains(t, networkIDs(networks.FilterByBlockType("sf.acme.type.v1.Block")), "acme-dummy-blockchain")
+	assert.Contains(t, networkIDs(networks.FilterByProtocol(registry.Ethereum)), "mainnet")
+}
@@ -0,0 +1,137 @@
+package networks
+
+import (
+	"maps"
+	"slices"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+)
+
+// networkIndex is the lookup index of a snapshot, built once when the snapshot is created so
+// the lookups of [Registry], like [Registry.Find] or [Registry.FindByCAIP2], don't scan the
+// registry. Like the snapshot, it's never modified afterwards.
+//
+// Networks sharing a key are sorted by ID, which gives the same precedence as the scans of
+// [NetworkRegistry.Find] and [NetworkRegistry.FindAll].
+type networkIndex struct {
+	// byKey maps IDs, aliases, full names, short names and CAIP-2 IDs to the networks having them.
+	byKey map[string][]*registry.Network
+
+	// byNormalizedID and byNormalizedKey are the same with keys normalized by [NormalizeKey],
+	// IDs being kept apart since they take precedence.
+	byNormalizedID  map[string][]*registry.Network
+	byNormalizedKey map[string][]*registry.Network
+
+	byCAIP2                map[string][]*registry.Network
+	byEVMChainID           map[uint64][]*registry.Network
+	byFirehoseEndpoint     map[string][]*registry.Network
+	bySubstreamsEndpoint   map[string][]*registry.Network
+	byFirstStreamableBlock map[firstStreamableBlock][]*registry.Network
+
+	// byEndpoint maps the Firehose and Substreams endpoints normalized by [NormalizeEndpoint].
+	byEndpoint map[string][]*registry.Network
+}
+
+// firstStreamableBlock is the key of a first streamable block, the ID being stored without its
+// 0x prefix.
+type firstStreamableBlock struct {
+	height uint64
+	id     string
+}
+
+func newNetworkIndex(networks NetworkRegistry) *networkIndex {
+	index := &networkIndex{
+		byKey:                  make(map[string][]*registry.Network, len(networks)*4),
+		byNormalizedID:         make(map[string][]*registry.Network, len(networks)),
+		byNormalizedKey:        make(map[string][]*registry.Network, len(networks)*4),
+		byCAIP2:                make(map[string][]*registry.Network, len(networks)),
+		byEVMChainID:           make(map[uint64][]*registry.Network, len(networks)),
+		byFirehoseEndpoint:     make(map[string][]*registry.Network),
+		bySubstreamsEndpoint:   make(map[string][]*registry.Network),
+		byEndpoint:             make(map[string][]*registry.Network),
+		byFirstStreamableBlock: make(map[firstStreamableBlock][]*registry.Network),
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(networks)) {
+		network := networks[id]
+
+		indexOnce(index.byKey, network, network.ID, network.FullName, network.ShortName, network.Caip2ID)
+		indexOnce(index.byKey, network, network.Aliases...)
+		indexOnce(index.byNormalizedID, network, NormalizeKey(network.ID))
+		indexOnce(index.byNormalizedKey, network, NormalizeKey(network.FullName), NormalizeKey(network.ShortName), NormalizeKey(network.Caip2ID))
+		for _, alias := range network.Aliases {
+			indexOnce(index.byNormalizedKey, network, NormalizeKey(alias))
+		}
+		indexOnce(index.byCAIP2, network, network.Caip2ID)
+		if chainID, ok := EVMChainID(network); ok {
+			index.byEVMChainID[chainID] = append(index.byEVMChainID[chainID], network)
+		}
+		indexOnce(index.byFirehoseEndpoint, network, network.Services.Firehose...)
+		indexOnce(index.bySubstreamsEndpoint, network, network.Services.Substreams...)
+		for _, endpoint := range slices.Concat(network.Services.Firehose, network.Services.Substreams) {
+			indexOnce(index.byEndpoint, network, NormalizeEndpoint(endpoint))
+		}
+
+		if network.Firehose != nil && network.Firehose.FirstStreamableBlock != nil {
+			block := network.Firehose.FirstStreamableBlock
+			indexOnce(index.byFirstStreamableBlock, network, firstStreamableBlock{uint64(block.Height), nox(block.ID)})
+		}
+	}
+
+	return index
+}
+
+// indexOnce adds network to the entries of keys, once even when keys has duplicates. Networks
+// are indexed in ID order, so network is always the last one of an entry it's already part of.
+func indexOnce[K comparable](entries map[K][]*registry.Network, network *registry.Network, keys ...K) {
+	var zero K
+	for _, key := range keys {
+		if key == zero {
+			continue
+		}
+
+		indexed := entries[key]
+		if len(indexed) > 0 && indexed[len(indexed)-1] == network {
+			continue
+		}
+
+		entries[key] = append(indexed, network)
+	}
+}
+
+// find returns the network with ID key or, if there is none, the first one having key as alias,
+// full name, short name or CAIP-2 ID, like [NetworkRegistry.Find].
+func (i *networkIndex) find(networks NetworkRegistry, key string) *registry.Network {
+	if network, found := networks[key]; found {
+		return network
+	}
+
+	return first(i.byKey[key])
+}
+
+// findNormalized is like find, falling back to keys normalized by [NormalizeKey] when nothing
+// matches key exactly.
+func (i *networkIndex) findNormalized(networks NetworkRegistry, key string) *registry.Network {
+	if network := i.find(networks, key); network != nil {
+		return network
+	}
+
+	normalized := NormalizeKey(key)
+	if network := first(i.byNormalizedID[normalized]); network != nil {
+		return network
+	}
+
+	return first(i.byNormalizedKey[normalized])
+}
+
+func (i *networkIndex) findAll(key string) []*registry.Network {
+	return slices.Clone(i.byKey[key])
+}
+
+func first(networks []*registry.Network) *registry.Network {
+	if len(networks) == 0 {
+		return nil
+	}
+
+	return networks[0]
+}
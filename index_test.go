@@ -0,0 +1,139 @@
+package networks
+
+import (
+	"context"
+	"testing"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadEmbeddedRegistry(t testing.TB) *Registry {
+	t.Helper()
+
+	r := New(WithSources(EmbeddedSource()))
+	require.NoError(t, r.Load(context.Background()))
+
+	return r
+}
+
+// TestRegistry_Find_Index checks the index against the scans of NetworkRegistry for every key of
+// the embedded registry.
+func TestRegistry_Find_Index(t *testing.T) {
+	r := loadEmbeddedRegistry(t)
+	networks := r.Networks()
+
+	keys := []string{"unknown", "Unknown"}
+	for _, network := range networks {
+		keys = append(keys, network.ID, network.FullName, network.ShortName, network.Caip2ID)
+		keys = append(keys, network.Aliases...)
+	}
+
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+
+		assert.Same(t, networks.Find(key), r.Find(key), "Find(%q)", key)
+		assert.Equal(t, networks.FindAll(key), r.FindAll(key), "FindAll(%q)", key)
+		assert.Equal(t, networks.Has(key), r.Has(key), "Has(%q)", key)
+	}
+
+	for _, network := range networks {
+		for _, endpoint := range network.Services.Substreams {
+			assert.Contains(t, networks.FindAll(network.ID), r.FindBySubstreamsEndpoint(endpoint), "FindBySubstreamsEndpoint(%q)", endpoint)
+		}
+
+		if network.Firehose != nil && network.Firehose.FirstStreamableBlock != nil {
+			block := network.Firehose.FirstStreamableBlock
+			found := r.FindByFirstStreamableBlock(uint64(block.Height), "0x"+nox(block.ID))
+			require.NotNil(t, found, "FindByFirstStreamableBlock(%d, %q)", block.Height, block.ID)
+			assert.Equal(t, block.ID, found.Firehose.FirstStreamableBlock.ID)
+		}
+	}
+}
+
+func TestNetworkIndex_Precedence(t *testing.T) {
+	networks := NetworkRegistry{
+		"beta":  {ID: "beta", Aliases: []string{"shared", "shared"}, ShortName: "alpha"},
+		"alpha": {ID: "alpha", FullName: "shared"},
+		"gamma": {ID: "gamma", ShortName: "gamma", Aliases: []string{"gamma"}},
+	}
+	index := newNetworkIndex(networks)
+
+	assert.Same(t, networks["alpha"], index.find(networks, "alpha"), "ID wins over the short name of a network sorted before")
+	assert.Same(t, networks["alpha"], index.find(networks, "shared"))
+	assert.Equal(t, []*registry.Network{networks["alpha"], networks["beta"]}, index.findAll("shared"))
+	assert.Equal(t, []*registry.Network{networks["gamma"]}, index.findAll("gamma"))
+	assert.Nil(t, index.find(networks, ""))
+	assert.Nil(t, index.findAll("unknown"))
+}
+
+var benchmarkKeys = []string{"mainnet", "eth", "Ethereum Mainnet", "arbitrum-one", "Arbitrum One", "unknown"}
+
+func BenchmarkFind(b *testing.B) {
+	r := loadEmbeddedRegistry(b)
+	networks := r.Networks()
+
+	for _, key := range benchmarkKeys {
+		b.Run(key+"/scan", func(b *testing.B) {
+			for b.Loop() {
+				networks.Find(key)
+			}
+		})
+
+		b.Run(key+"/index", func(b *testing.B) {
+			for b.Loop() {
+				r.Find(key)
+			}
+		})
+	}
+}
+
+func BenchmarkFindAll(b *testing.B) {
+	r := loadEmbeddedRegistry(b)
+	networks := r.Networks()
+
+	for _, key := range benchmarkKeys {
+		b.Run(key+"/scan", func(b *testing.B) {
+			for b.Loop() {
+				networks.FindAll(key)
+			}
+		})
+
+		b.Run(key+"/index", func(b *testing.B) {
+			for b.Loop() {
+				r.FindAll(key)
+			}
+		})
+	}
+}
+
+func BenchmarkHas(b *testing.B) {
+	r := loadEmbeddedRegistry(b)
+	networks := r.Networks()
+
+	for _, key := range benchmarkKeys {
+		b.Run(key+"/scan", func(b *testing.B) {
+			for b.Loop() {
+				networks.Has(key)
+			}
+		})
+
+		b.Run(key+"/index", func(b *testing.B) {
+			for b.Loop() {
+				r.Has(key)
+			}
+		})
+	}
+}
+
+func BenchmarkNewNetworkIndex(b *testing.B) {
+	networks := loadEmbeddedRegistry(b).Networks()
+	b.Logf("indexing %d networks", len(networks))
+
+	for b.Loop() {
+		newNetworkIndex(networks)
+	}
+}
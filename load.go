@@ -0,0 +1,416 @@
+package networks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+	"go.uber.org/zap"
+)
+
+// defaultLoadTimeout is the default of [WithLoadTimeout].
+const defaultLoadTimeout = 10 * time.Second
+
+// Load loads the registry from its sources if it's not loaded yet, returning a [*LoadError]
+// describing why each source failed when none succeeded. Calling it is optional, the first
+// lookup loads the registry implicitly, but it gives control over how long the loading can
+// take and how failures are handled.
+//
+// When ctx is done, the source being loaded is abandoned and the remaining ones are still
+// tried with the done context: sources doing I/O fail right away while local ones like
+// [EmbeddedSource] succeed, so a short deadline gets a quick start on the embedded registry.
+// When the registry is loaded from another source than the first one, the preceding sources
+// are retried in the background until one succeeds.
+//
+// Invalid options, like a malformed [WithVersion] constraint, fail right away without trying
+// any source. Overrides files that cannot be loaded are skipped: the registry is loaded without
+// them, the reason being returned by the first call, which reads them, and reported by
+// [RegistryStatus.OverridesError].
+func (r *Registry) Load(ctx context.Context) error {
+	// Deferred first so subscribers are notified once loadLock is released.
+	defer r.notify()
+
+	r.loadLock.Lock()
+	defer r.loadLock.Unlock()
+
+	var overridesErr error
+	r.overridesOnce.Do(func() { overridesErr = r.addOverridesFiles() })
+	if r.configErr != nil {
+		return fmt.Errorf("invalid registry configuration: %w", r.configErr)
+	}
+
+	if snap := r.current.Load(); snap == nil || !snap.loaded(r) {
+		snap, err := r.loadChain(ctx, len(r.sources))
+		if err != nil {
+			return err
+		}
+
+		r.activate(snap)
+	}
+
+	return overridesErr
+}
+
+// MustLoad is like [Registry.Load] but panics when the registry cannot be loaded. Skipped
+// overrides files don't make it panic.
+func (r *Registry) MustLoad(ctx context.Context) {
+	if err := r.Load(ctx); err != nil {
+		if snap := r.current.Load(); snap == nil || !snap.loaded(r) {
+			panic(err)
+		}
+	}
+}
+
+// Load is a shortcut for [Registry.Load] on the default registry.
+func Load(ctx context.Context) error {
+	return defaultRegistry.Load(ctx)
+}
+
+// MustLoad is a shortcut for [Registry.MustLoad] on the default registry.
+func MustLoad(ctx context.Context) {
+	defaultRegistry.MustLoad(ctx)
+}
+
+// snapshot returns the active snapshot, loading it on first call with the load timeout. When
+// all sources fail, a registry holding only the custom networks is used while the sources are
+// retried in the background.
+func (r *Registry) snapshot() *snapshot {
+	if snap := r.current.Load(); snap != nil {
+		return snap
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.loadTimeout)
+	defer cancel()
+
+	if err := r.Load(ctx); err != nil {
+		r.loadLock.Lock()
+		if r.current.Load() == nil {
+			if r.configErr != nil {
+				r.logger.Error("invalid registry configuration, using custom networks only", zap.Error(err))
+			} else {
+				r.logger.Error("failed to load registry from all sources, using custom networks only until one succeeds", zap.Error(err))
+			}
+			r.activate(r.buildSnapshot(&registry.NetworksRegistry{}, len(r.sources)))
+		}
+		r.loadLock.Unlock()
+		r.notify()
+	}
+
+	return r.current.Load()
+}
+
+// activate installs snap and, when it doesn't come from the first source, starts retrying the
+// preceding sources in the background. There is nothing to retry with an invalid configuration.
+//
+// It's called while holding loadLock, subscribers are notified by the caller once released.
+func (r *Registry) activate(snap *snapshot) {
+	r.install(snap)
+
+	if snap.sourceIndex > 0 && r.configErr == nil && r.retrying.CompareAndSwap(false, true) {
+		// The network registry could not be loaded from the preferred sources, we
+		// launch a Go routine that is going to retry them exponentially and replace
+		// the snapshot. It's started only once the snapshot is installed so it cannot
+		// be overwritten by it.
+		go func() {
+			defer r.retrying.Store(false)
+			r.backgroundUpdateLatestRegistry(context.Background(), snap.sourceIndex)
+		}()
+	}
+}
+
+// loadChain tries the first limit sources in order and returns the snapshot of the first
+// one loading successfully.
+func (r *Registry) loadChain(ctx context.Context, limit int) (*snapshot, error) {
+	if r.configErr != nil {
+		return nil, fmt.Errorf("invalid registry configuration: %w", r.configErr)
+	}
+
+	loadErr := &LoadError{}
+	for i, source := range r.sources[:limit] {
+		snap, err := r.loadSnapshot(ctx, i)
+		if err == nil {
+			r.refreshStatus.record(nil)
+			if i > 0 {
+				r.logger.Info("loaded registry from fallback source", zap.String("source", source.Name()), zap.String("version", snap.version))
+			}
+
+			return snap, nil
+		}
+
+		var verificationErr *VerificationError
+		if errors.As(err, &verificationErr) {
+			r.logger.Warn("rejected registry document failing verification", zap.String("source", source.Name()), zap.Error(err))
+		} else {
+			r.logger.Debug("failed to load registry from source", zap.String("source", source.Name()), zap.Error(err))
+		}
+
+		loadErr.Failures = append(loadErr.Failures, &SourceError{Source: source.Name(), Err: err})
+	}
+
+	r.refreshStatus.record(loadErr)
+	return nil, loadErr
+}
+
+// loadSnapshot loads the registry from the source at index and builds a snapshot out of it, the
+// loaded registry is written to the cache directory first when the source precedes the cache.
+//
+// When the source is the one the active snapshot comes from, its version is given to the source
+// through [ActiveVersion] and the active snapshot is returned as is on [ErrNotModified].
+func (r *Registry) loadSnapshot(ctx context.Context, index int) (*snapshot, error) {
+	active := r.current.Load()
+	if active != nil && active.sourceIndex == index {
+		ctx = withActiveVersion(ctx, active.version)
+	} else {
+		active = nil
+	}
+
+	if len(r.verifiers) > 0 {
+		ctx = withVerifiers(ctx, r.verifiers)
+	}
+
+	ctx, document := withVerifiedDocument(ctx)
+
+	start := time.Now()
+	nativeRegistry, fetchedAt, err := loadSource(ctx, r.sources[index])
+	if active != nil && errors.Is(err, ErrNotModified) {
+		r.metrics.observeLoad(r.sources[index].Name(), time.Since(start), nil)
+		r.logger.Debug("registry not modified", zap.String("source", active.source), zap.String("version", active.version))
+		return active, nil
+	}
+
+	if err == nil {
+		err = r.checkVersion(nativeRegistry)
+	}
+
+	r.metrics.observeLoad(r.sources[index].Name(), time.Since(start), err)
+	if err != nil {
+		return nil, err
+	}
+
+	if index < r.cacheIndex {
+		if err := writeRegistryCache(r.cacheDir, nativeRegistry, document, fetchedAt); err != nil {
+			r.logger.Warn("failed to write registry cache", zap.String("cache_dir", r.cacheDir), zap.Error(err))
+		}
+	}
+
+	snap := r.buildSnapshot(nativeRegistry, index)
+	snap.loadedAt = fetchedAt
+
+	return snap, nil
+}
+
+// loadSource loads the registry from source along with when it was fetched, which is now except
+// for the cache holding a registry fetched earlier.
+func loadSource(ctx context.Context, source Source) (*registry.NetworksRegistry, time.Time, error) {
+	if cache, ok := source.(*cacheSource); ok {
+		return cache.loadFetchedAt(ctx)
+	}
+
+	nativeRegistry, err := source.Load(ctx)
+	return nativeRegistry, time.Now(), err
+}
+
+// buildSnapshot applies the overrides, including the ones registered at runtime, to the registry
+// loaded from the source at index and builds a snapshot out of it. The loaded registry is never
+// modified so it can be built again when a registration happens.
+func (r *Registry) buildSnapshot(nativeRegistry *registry.NetworksRegistry, index int) *snapshot {
+	registry := NewNetworkRegistry(nativeRegistry)
+	registeredNetworks, registeredServices, generation := r.registrations.get()
+
+	source := ""
+	if index < len(r.sources) {
+		source = r.sources[index].Name()
+	}
+	tracker := newProvenanceTracker(Provenance{Kind: ProvenanceRegistry, Source: source, Version: nativeRegistry.Version})
+
+	var overrideReports []OverrideReport
+	for _, custom := range slices.Concat(r.networkOverrides, registeredNetworks) {
+		// Copied so that the registry never holds a network the caller may still modify.
+		network := cloneNetwork(custom.network)
+		added := registry.addCustomNetwork(network, custom.forced)
+		if added {
+			tracker.network(network.ID, custom.origin)
+		}
+
+		overrideReports = append(overrideReports, networkOverrideReport(custom, added))
+	}
+
+	for _, custom := range slices.Concat(r.serviceOverrides, registeredServices) {
+		before, found := registry[custom.override.NetworkID]
+		registry.addServiceEndpoints(custom.override)
+		if found {
+			tracker.endpoints(before.ID, before.Services, registry[before.ID].Services, custom.origin)
+		}
+
+		overrideReports = append(overrideReports, serviceOverrideReport(custom, before))
+	}
+
+	var patchResults []PatchResult
+	for _, custom := range r.patches {
+		before := registry[custom.patch.NetworkID]
+		status := registry.applyPatch(custom.patch)
+		switch status {
+		case PatchApplied:
+			tracker.endpoints(before.ID, before.Services, registry[before.ID].Services, custom.origin)
+			tracker.patch(before.ID, custom.origin)
+		default:
+			r.logger.Debug("registry patch not applied", zap.String("network", custom.patch.NetworkID), zap.String("status", string(status)))
+		}
+
+		patchResults = append(patchResults, PatchResult{Patch: custom.patch, Status: status})
+		overrideReports = append(overrideReports, patchOverrideReport(custom, status))
+	}
+
+	for _, report := range overrideReports {
+		if report.Stale() {
+			r.logger.Debug("stale registry override", zap.String("kind", string(report.Kind)), zap.String("network", report.NetworkID), zap.Stringer("origin", report.Origin), zap.String("status", string(report.Status)))
+		}
+	}
+
+	snap := newSnapshot(nativeRegistry, registry)
+	snap.patchResults = patchResults
+	snap.overrideReports = overrideReports
+	snap.provenance = tracker.networks
+	snap.generation = generation
+	snap.source = source
+	snap.sourceIndex = index
+
+	return snap
+}
+
+// rebuildSnapshot builds snap again from the same loaded registry, to apply the registrations
+// that happened since it was built.
+func (r *Registry) rebuildSnapshot(snap *snapshot) *snapshot {
+	rebuilt := r.buildSnapshot(snap.native, snap.sourceIndex)
+	rebuilt.loadedAt = snap.loadedAt
+
+	return rebuilt
+}
+
+// refresh installs snap in place of the active snapshot and notifies subscribers about it.
+func (r *Registry) refresh(snap *snapshot) {
+	r.install(snap)
+	r.notify()
+}
+
+// install installs snap in place of the active snapshot, concurrent readers either see the
+// previous snapshot or the new one but never a mix of both. Installing the active snapshot again,
+// when its source was not modified, does nothing. Subscribers are notified by [Registry.notify].
+//
+// A snapshot coming from a less preferred source than the active one is dropped: it was loaded
+// while the preferred source was failing, which a concurrent refresh installed since.
+func (r *Registry) install(snap *snapshot) {
+	r.refreshLock.Lock()
+	if active := r.current.Load(); active != nil && active.loaded(r) && snap.sourceIndex > active.sourceIndex {
+		r.refreshLock.Unlock()
+		r.logger.Debug("dropped registry from a less preferred source than the active one", zap.String("source", snap.source), zap.String("active_source", active.source))
+		return
+	}
+
+	if snap.generation != r.registrations.currentGeneration() {
+		snap = r.rebuildSnapshot(snap)
+	}
+	previous := r.current.Swap(snap)
+	if previous != nil && previous != snap {
+		r.subscribers.enqueue(previous, snap)
+	}
+	r.refreshLock.Unlock()
+
+	if previous != snap {
+		r.observeSnapshot(snap)
+	}
+}
+
+// reapplyOverrides rebuilds the active snapshot, if any, to apply the registrations.
+func (r *Registry) reapplyOverrides() {
+	r.refreshLock.Lock()
+	previous := r.current.Load()
+	if previous == nil {
+		r.refreshLock.Unlock()
+		return
+	}
+
+	snap := r.rebuildSnapshot(previous)
+	r.current.Store(snap)
+	r.subscribers.enqueue(previous, snap)
+	r.refreshLock.Unlock()
+
+	r.observeSnapshot(snap)
+	r.notify()
+}
+
+// observeSnapshot reports the installation of snap to the metrics.
+func (r *Registry) observeSnapshot(snap *snapshot) {
+	if snap.loaded(r) {
+		r.metrics.observeSnapshot(snap, sourceKind(r.sources[snap.sourceIndex]))
+	}
+}
+
+// backgroundUpdateLatestRegistry retries the first limit sources until one of them loads, and
+// then keeps retrying the ones preceding it until the first source of the chain is loaded.
+func (r *Registry) backgroundUpdateLatestRegistry(ctx context.Context, limit int) {
+	for limit > 0 {
+		operation := func() (*snapshot, error) {
+			ctx, cancel := context.WithTimeout(ctx, r.loadTimeout)
+			defer cancel()
+
+			return r.loadChain(ctx, limit)
+		}
+
+		// Neither the tries nor the elapsed time, 15 minutes by default, are limited: retries
+		// only stop once a source loads.
+		snap, err := backoff.Retry(ctx, operation, backoff.WithMaxTries(0), backoff.WithMaxElapsedTime(0), backoff.WithBackOff(backoff.NewExponentialBackOff()))
+		if err != nil {
+			// We have been cancelled, nothing to do more
+			return
+		}
+
+		r.refresh(snap)
+		limit = r.current.Load().sourceIndex
+	}
+}
+
+// ScheduleUpdateLatestRegistry schedules a background update goroutine of the latest registry at the
+// specified interval. It runs in a goroutine and updates the registry views, logging through the
+// registry's logger. You can control it with a context to stop the updates gracefully.
+func (r *Registry) ScheduleUpdateLatestRegistry(ctx context.Context, interval time.Duration) {
+	r.scheduleUpdateLatestRegistry(ctx, interval, r.logger)
+}
+
+// loadLatest loads the registry from the source in use or a preferred one, bounded by the
+// load timeout.
+func (r *Registry) loadLatest(ctx context.Context) (*snapshot, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.loadTimeout)
+	defer cancel()
+
+	return r.loadChain(ctx, min(r.snapshot().sourceIndex+1, len(r.sources)))
+}
+
+func (r *Registry) scheduleUpdateLatestRegistry(ctx context.Context, interval time.Duration, logger *zap.Logger) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				// Exit if context is cancelled
+				logger.Debug("stopping background registry update due to context cancellation")
+				return
+
+			case <-ticker.C:
+				snap, err := r.loadLatest(ctx)
+				if err != nil {
+					logger.Info("failed to load latest registry, skipping this interval update", zap.Error(err))
+					continue
+				}
+
+				r.refresh(snap)
+			}
+		}
+	}()
+}
@@ -0,0 +1,128 @@
+package networks
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowSource blocks until its context is done, like a remote host that never answers.
+var slowSource = SourceFunc("slow", func(ctx context.Context) (*registry.NetworksRegistry, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+})
+
+func TestRegistry_Load(t *testing.T) {
+	t.Run("loads once", func(t *testing.T) {
+		var calls atomic.Int64
+		source := SourceFunc("counting", func(ctx context.Context) (*registry.NetworksRegistry, error) {
+			calls.Add(1)
+			return staticSource(registry.Network{ID: "alpha"}).Load(ctx)
+		})
+
+		r := New(WithSources(source))
+		require.NoError(t, r.Load(context.Background()))
+		require.NoError(t, r.Load(context.Background()))
+
+		assert.True(t, r.Has("alpha"))
+		assert.Equal(t, int64(1), calls.Load())
+	})
+
+	t.Run("reports every source failure", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		r := New(WithSources(slowSource, failingSource))
+		err := r.Load(ctx)
+
+		var loadErr *LoadError
+		require.ErrorAs(t, err, &loadErr)
+		require.Len(t, loadErr.Failures, 2)
+		assert.Equal(t, "slow", loadErr.Failures[0].Source)
+		assert.ErrorIs(t, loadErr.Failures[0], context.DeadlineExceeded)
+		assert.Equal(t, "failing", loadErr.Failures[1].Source)
+		assert.EqualError(t, loadErr.Failures[1], "source failing: registry unavailable")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		assert.Panics(t, func() { r.MustLoad(ctx) })
+	})
+
+	t.Run("deadline still gets the embedded registry", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		r := New(WithSources(slowSource, EmbeddedSource()))
+		start := time.Now()
+		require.NoError(t, r.Load(ctx))
+
+		assert.Less(t, time.Since(start), time.Second)
+		assert.True(t, r.Has("mainnet"))
+		assert.Equal(t, "embedded", r.snapshot().source)
+	})
+
+	t.Run("loads again after a failure", func(t *testing.T) {
+		var available atomic.Bool
+		source := SourceFunc("flaky", func(ctx context.Context) (*registry.NetworksRegistry, error) {
+			if !available.Load() {
+				return failingSource.Load(ctx)
+			}
+
+			return staticSource(registry.Network{ID: "alpha"}).Load(ctx)
+		})
+
+		r := New(WithSources(source))
+		require.Error(t, r.Load(context.Background()))
+
+		available.Store(true)
+		require.NoError(t, r.Load(context.Background()))
+		assert.True(t, r.Has("alpha"))
+	})
+}
+
+func TestRegistry_ImplicitLoad(t *testing.T) {
+	t.Run("bounded by the load timeout", func(t *testing.T) {
+		r := New(WithSources(slowSource, EmbeddedSource()), WithLoadTimeout(10*time.Millisecond))
+
+		start := time.Now()
+		assert.NotNil(t, r.Find("mainnet"))
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("does not panic when all sources fail", func(t *testing.T) {
+		var available atomic.Bool
+		source := SourceFunc("flaky", func(ctx context.Context) (*registry.NetworksRegistry, error) {
+			if !available.Load() {
+				return failingSource.Load(ctx)
+			}
+
+			return staticSource(registry.Network{ID: "alpha"}).Load(ctx)
+		})
+
+		r := New(WithSources(source), WithNetworks(&registry.Network{ID: "custom"}))
+
+		assert.NotPanics(t, func() {
+			assert.Nil(t, r.Find("alpha"))
+			assert.NotNil(t, r.Find("custom"))
+		})
+
+		// Sources are retried in the background and replace the custom networks only registry
+		available.Store(true)
+		assert.Eventually(t, func() bool { return r.Has("alpha") }, 5*time.Second, time.Millisecond)
+		assert.True(t, r.Has("custom"))
+	})
+}
+
+func TestLoadError(t *testing.T) {
+	sourceErr := errors.New("boom")
+	err := &LoadError{Failures: []*SourceError{{Source: "first", Err: sourceErr}, {Source: "second", Err: errors.New("bang")}}}
+
+	assert.EqualError(t, err, "load registry: all sources failed: source first: boom; source second: bang")
+	assert.ErrorIs(t, err, sourceErr)
+	assert.EqualError(t, &LoadError{}, "load registry: no source configured")
+}
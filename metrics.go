@@ -0,0 +1,109 @@
+package networks
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// WithMetrics registers Prometheus collectors instrumenting the registry loads and refreshes
+// with registerer, nothing is registered unless this option is used. The collectors are named
+// `firehose_networks_registry_*`, wrap registerer with [prometheus.WrapRegistererWith] to
+// distinguish several registries registered with the same registerer.
+func WithMetrics(registerer prometheus.Registerer) Option {
+	return func(r *Registry) {
+		r.metricsRegisterer = registerer
+	}
+}
+
+// metrics holds the collectors of a [Registry], a nil *metrics is valid and records nothing.
+type metrics struct {
+	loadAttempts     *prometheus.CounterVec
+	loadFailures     *prometheus.CounterVec
+	loadDuration     *prometheus.HistogramVec
+	info             *prometheus.GaugeVec
+	networks         *prometheus.GaugeVec
+	embeddedFallback prometheus.Gauge
+}
+
+func newMetrics(r *Registry, registerer prometheus.Registerer) *metrics {
+	m := &metrics{
+		loadAttempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "firehose_networks_registry_refresh_attempts_total",
+			Help: "Number of attempts to load the registry from each source, initial load included.",
+		}, []string{"source"}),
+		loadFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "firehose_networks_registry_refresh_failures_total",
+			Help: "Number of failed attempts to load the registry from each source, initial load included.",
+		}, []string{"source"}),
+		loadDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "firehose_networks_registry_fetch_duration_seconds",
+			Help:    "Time taken to load the registry from each source, failed attempts included.",
+			Buckets: []float64{0.001, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		}, []string{"source"}),
+		info: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "firehose_networks_registry_info",
+			Help: "Always 1, labelled with the version of the registry in use and the source it comes from.",
+		}, []string{"version", "source", "source_kind"}),
+		networks: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "firehose_networks_registry_networks",
+			Help: "Number of networks in each view of the registry in use.",
+		}, []string{"view"}),
+		embeddedFallback: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "firehose_networks_registry_embedded_fallback",
+			Help: "1 while the registry in use is the one embedded in the binary, 0 otherwise.",
+		}),
+	}
+
+	snapshotAge := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "firehose_networks_registry_snapshot_age_seconds",
+		Help: "Time elapsed since the registry in use was fetched from its source, the cache included, 0 until loaded.",
+	}, func() float64 {
+		snap := r.current.Load()
+		if snap == nil || !snap.loaded(r) {
+			return 0
+		}
+
+		return time.Since(snap.loadedAt).Seconds()
+	})
+
+	for _, collector := range []prometheus.Collector{m.loadAttempts, m.loadFailures, m.loadDuration, m.info, m.networks, m.embeddedFallback, snapshotAge} {
+		if err := registerer.Register(collector); err != nil {
+			r.logger.Warn("failed to register registry metric", zap.Error(err))
+		}
+	}
+
+	return m
+}
+
+func (m *metrics) observeLoad(source string, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+
+	m.loadAttempts.WithLabelValues(source).Inc()
+	m.loadDuration.WithLabelValues(source).Observe(duration.Seconds())
+	if err != nil {
+		m.loadFailures.WithLabelValues(source).Inc()
+	}
+}
+
+func (m *metrics) observeSnapshot(snap *snapshot, kind SourceKind) {
+	if m == nil {
+		return
+	}
+
+	m.info.Reset()
+	m.info.WithLabelValues(snap.version, snap.source, string(kind)).Set(1)
+
+	m.networks.WithLabelValues("full").Set(float64(len(snap.full)))
+	m.networks.WithLabelValues("firehose").Set(float64(len(snap.firehose)))
+	m.networks.WithLabelValues("substreams").Set(float64(len(snap.substreams)))
+
+	if kind == SourceKindEmbedded {
+		m.embeddedFallback.Set(1)
+	} else {
+		m.embeddedFallback.Set(0)
+	}
+}
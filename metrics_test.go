@@ -0,0 +1,55 @@
+package networks
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_WithMetrics(t *testing.T) {
+	registerer := prometheus.NewPedanticRegistry()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	r := New(WithSources(failingSource, EmbeddedSource()), WithMetrics(registerer))
+	require.NoError(t, r.Load(ctx))
+
+	assert.NoError(t, testutil.GatherAndCompare(registerer, strings.NewReader(`
+# HELP firehose_networks_registry_embedded_fallback 1 while the registry in use is the one embedded in the binary, 0 otherwise.
+# TYPE firehose_networks_registry_embedded_fallback gauge
+firehose_networks_registry_embedded_fallback 1
+# HELP firehose_networks_registry_info Always 1, labelled with the version of the registry in use and the source it comes from.
+# TYPE firehose_networks_registry_info gauge
+firehose_networks_registry_info{source="embedded",source_kind="embedded",version="0.7.34"} 1
+`), "firehose_networks_registry_embedded_fallback", "firehose_networks_registry_info"))
+
+	// The failing source is retried in the background right away, it may have been attempted more than once
+	assert.Equal(t, float64(1), testutil.ToFloat64(r.metrics.loadAttempts.WithLabelValues("embedded")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(r.metrics.loadFailures.WithLabelValues("embedded")))
+	assert.GreaterOrEqual(t, testutil.ToFloat64(r.metrics.loadAttempts.WithLabelValues("failing")), float64(1))
+	assert.GreaterOrEqual(t, testutil.ToFloat64(r.metrics.loadFailures.WithLabelValues("failing")), float64(1))
+
+	snap := r.snapshot()
+	assert.Equal(t, float64(len(snap.full)), testutil.ToFloat64(r.metrics.networks.WithLabelValues("full")))
+	assert.Equal(t, float64(len(snap.firehose)), testutil.ToFloat64(r.metrics.networks.WithLabelValues("firehose")))
+	assert.Equal(t, float64(len(snap.substreams)), testutil.ToFloat64(r.metrics.networks.WithLabelValues("substreams")))
+	assert.GreaterOrEqual(t, testutil.CollectAndCount(r.metrics.loadDuration), 2, "one histogram per source")
+
+	count, err := testutil.GatherAndCount(registerer, "firehose_networks_registry_snapshot_age_seconds")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestRegistry_WithoutMetrics(t *testing.T) {
+	r := New(WithSources(staticSource()))
+
+	assert.Nil(t, r.metrics)
+	assert.NoError(t, r.Load(context.Background()), "loading must work without metrics")
+}
@@ -3,15 +3,14 @@ package networks
 import (
 	"context"
 	_ "embed"
+	"encoding/json"
 	"fmt"
 	"maps"
 	"regexp"
 	"slices"
 	"strings"
-	"sync"
 	"time"
 
-	"github.com/cenkalti/backoff/v5"
 	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
 	"go.uber.org/zap"
 )
@@ -23,61 +22,34 @@ var embeddedRegistryJSON []byte
 // where we map from [registry.Network.ID] to [*registry.Network].
 type NetworkRegistry map[string]*registry.Network
 
-var (
-	registryNetworksFull       NetworkRegistry
-	registryNetworksFirehose   NetworkRegistry
-	registryNetworksSubstreams NetworkRegistry
-	registryNetworksOnce       sync.Once
-)
-
-// getRegistryNetworks fetches and caches all networks from the registry (no filtering).
-func getRegistryNetworks() (full NetworkRegistry, firehose NetworkRegistry, substreams NetworkRegistry) {
-	registryNetworksOnce.Do(func() {
-		reg, err := loadRegistry(registry.FromLatestVersion)
-		if err != nil {
-			// If the network registry cannot be loaded from the latest version,
-			// we launch a Go routine that is going to retry exponentially (with a
-			// limit) and update the global registryNetworks variable.
-			go backgroundUpdateLatestRegistry(context.Background())
-
-			// Fallback, use embedded JSON
-			reg, err = loadRegistry(fromEmbeddedJSON)
-			if err != nil {
-				panic(fmt.Sprintf("Failed to load registry from both network and embedded JSON: %v", err))
-			}
-		}
-
-		setRegistries(reg)
-	})
-
-	return registryNetworksFull, registryNetworksFirehose, registryNetworksSubstreams
-}
+// NewNetworkRegistry maps the networks of a registry document by ID, as-is without applying
+// any of the overrides a [Registry] would apply.
+func NewNetworkRegistry(native *registry.NetworksRegistry) NetworkRegistry {
+	networks := make(NetworkRegistry, len(native.Networks))
+	for i, net := range native.Networks {
+		networks[net.ID] = &native.Networks[i]
+	}
 
-func getRegistryNetworksFull() NetworkRegistry {
-	full, _, _ := getRegistryNetworks()
-	return full
+	return networks
 }
 
-func loadRegistry(loader func() (*registry.NetworksRegistry, error)) (NetworkRegistry, error) {
-	nativeRegistry, err := loader()
-	if err != nil {
-		return nil, err
-	}
-
-	registry := NetworkRegistry{}
-	for i, net := range nativeRegistry.Networks {
-		registry[net.ID] = &nativeRegistry.Networks[i]
+// cloneNetwork returns a deep copy of network, sharing none of its slices and pointed to values.
+func cloneNetwork(network *registry.Network) *registry.Network {
+	if network == nil {
+		return nil
 	}
 
-	for _, net := range networkOverrides {
-		registry.addCustomNetwork(net, false)
+	content, err := json.Marshal(network)
+	if err != nil {
+		panic(fmt.Errorf("network %q should always be marshallable: %w", network.ID, err))
 	}
 
-	for _, override := range serviceOverrides {
-		registry.addServiceEndpoints(override)
+	clone := &registry.Network{}
+	if err := json.Unmarshal(content, clone); err != nil {
+		panic(fmt.Errorf("network %q should always be unmarshallable: %w", network.ID, err))
 	}
 
-	return registry, nil
+	return clone
 }
 
 func fromEmbeddedJSON() (*registry.NetworksRegistry, error) {
@@ -86,19 +58,17 @@ func fromEmbeddedJSON() (*registry.NetworksRegistry, error) {
 
 // GetRegistry returns the full network registry without any filtering.
 func GetRegistry() NetworkRegistry {
-	return getRegistryNetworksFull()
+	return defaultRegistry.Networks()
 }
 
 // GetSubstreamsRegistry returns only networks with Substreams endpoints.
 func GetSubstreamsRegistry() NetworkRegistry {
-	_, _, registryNetworksSubstreams := getRegistryNetworks()
-	return registryNetworksSubstreams
+	return defaultRegistry.SubstreamsNetworks()
 }
 
 // GetFirehoseRegistry returns only networks with Firehose endpoints.
 func GetFirehoseRegistry() NetworkRegistry {
-	_, registryNetworksFirehose, _ := getRegistryNetworks()
-	return registryNetworksFirehose
+	return defaultRegistry.FirehoseNetworks()
 }
 
 func isSubstreamsNetwork(net *registry.Network) bool {
@@ -121,23 +91,26 @@ func (r NetworkRegistry) Filter(shouldInclude func(*registry.Network) bool) Netw
 }
 
 // addCustomNetwork can be used to add a custom network to the registry map for testing or development.
-func (r NetworkRegistry) addCustomNetwork(network *registry.Network, forced bool) {
+// It returns true when the network was added.
+func (r NetworkRegistry) addCustomNetwork(network *registry.Network, forced bool) bool {
 	if network == nil || network.ID == "" {
-		return // Ignore invalid input
+		return false // Ignore invalid input
 	}
 
 	_, found := r[network.ID]
 	if found && !forced {
 		// If the network already exists and not forced, we skip adding it.
-		return
+		return false
 	}
 
 	r[network.ID] = network
+	return true
 }
 
-// addServiceEndpoints merges the endpoints of a [serviceOverride] into the network it targets.
-// If the targeted network is not part of the registry, the override is ignored.
-func (r NetworkRegistry) addServiceEndpoints(override *serviceOverride) {
+// addServiceEndpoints merges the endpoints of a [ServiceOverride] into the network it targets.
+// If the targeted network is not part of the registry, the override is ignored. The network is
+// copied before being modified, like for [NetworkPatch].
+func (r NetworkRegistry) addServiceEndpoints(override *ServiceOverride) {
 	if override == nil || override.NetworkID == "" {
 		return // Ignore invalid input
 	}
@@ -147,11 +120,13 @@ func (r NetworkRegistry) addServiceEndpoints(override *serviceOverride) {
 		return
 	}
 
-	network.Services.Firehose = mergeEndpoints(override.Firehose, network.Services.Firehose)
-	network.Services.Substreams = mergeEndpoints(override.Substreams, network.Services.Substreams)
+	augmented := *network
+	augmented.Services.Firehose = mergeEndpoints(override.Firehose, network.Services.Firehose)
+	augmented.Services.Substreams = mergeEndpoints(override.Substreams, network.Services.Substreams)
+	r[override.NetworkID] = &augmented
 }
 
-// Has returns true if network exists, either by ID or by alias (sorted by network ID), FullName, and ShortName.
+// Has returns true if network exists, either by ID or by alias (sorted by network ID), FullName, ShortName, and CAIP-2 ID.
 func (r NetworkRegistry) Has(key string) bool {
 	if _, ok := r[key]; ok {
 		return true
@@ -160,7 +135,7 @@ func (r NetworkRegistry) Has(key string) bool {
 	return r.Find(key) != nil
 }
 
-// Find returns the network by ID or, if not found, by alias (sorted by network ID), FullName, and ShortName.
+// Find returns the network by ID or, if not found, by alias (sorted by network ID), FullName, ShortName, and CAIP-2 ID.
 func (r NetworkRegistry) Find(key string) *registry.Network {
 	if n, ok := r[key]; ok {
 		return n
@@ -169,14 +144,14 @@ func (r NetworkRegistry) Find(key string) *registry.Network {
 	slices.Sort(ids)
 	for _, id := range ids {
 		net := r[id]
-		if slices.Contains(net.Aliases, key) || net.FullName == key || net.ShortName == key || net.ID == key {
+		if slices.Contains(net.Aliases, key) || net.FullName == key || net.ShortName == key || net.ID == key || net.Caip2ID == key {
 			return net
 		}
 	}
 	return nil
 }
 
-// FindAll returns all networks matching the given key by alias, FullName, ShortName, or ID.
+// FindAll returns all networks matching the given key by alias, FullName, ShortName, CAIP-2 ID, or ID.
 func (r NetworkRegistry) FindAll(key string) []*registry.Network {
 	ids := slices.Collect(maps.Keys(r))
 	slices.Sort(ids)
@@ -184,7 +159,7 @@ func (r NetworkRegistry) FindAll(key string) []*registry.Network {
 	var results []*registry.Network
 	for _, id := range ids {
 		net := r[id]
-		if slices.Contains(net.Aliases, key) || net.FullName == key || net.ShortName == key || net.ID == key {
+		if slices.Contains(net.Aliases, key) || net.FullName == key || net.ShortName == key || net.ID == key || net.Caip2ID == key {
 			results = append(results, net)
 		}
 	}
@@ -192,7 +167,7 @@ func (r NetworkRegistry) FindAll(key string) []*registry.Network {
 	return results
 }
 
-// Search returns all networks matching the given regular expression against aliases, FullName, ShortName, or ID.
+// Search returns all networks matching the given regular expression against aliases, FullName, ShortName, CAIP-2 ID, or ID.
 func (r NetworkRegistry) Search(re *regexp.Regexp) []*registry.Network {
 	ids := slices.Collect(maps.Keys(r))
 	slices.Sort(ids)
@@ -200,8 +175,8 @@ func (r NetworkRegistry) Search(re *regexp.Regexp) []*registry.Network {
 	var results []*registry.Network
 	for _, id := range ids {
 		net := r[id]
-		// Check if pattern matches ID, FullName, ShortName, or any alias
-		if re.MatchString(net.ID) || re.MatchString(net.FullName) || re.MatchString(net.ShortName) {
+		// Check if pattern matches ID, FullName, ShortName, CAIP-2 ID, or any alias
+		if re.MatchString(net.ID) || re.MatchString(net.FullName) || re.MatchString(net.ShortName) || re.MatchString(net.Caip2ID) {
 			results = append(results, net)
 			continue
 		}
@@ -239,63 +214,67 @@ func (r NetworkRegistry) FindByFirstStreamableBlock(blockNum uint64, blockID str
 // Has is a shortcut for [NetworkRegistry.Has] which is
 // equivalent to `GetRegistry().Has(key)`.
 func Has(key string) bool {
-	return getRegistryNetworksFull().Has(key)
+	return defaultRegistry.Has(key)
 }
 
 // Find is a shortcut for [NetworkRegistry.Find] which is
 // equivalent to `GetRegistry().Find(key)`.
 func Find(key string) *registry.Network {
-	return getRegistryNetworksFull().Find(key)
+	return defaultRegistry.Find(key)
 }
 
 // FindAll is a shortcut for [NetworkRegistry.FindAll] which
 // is equivalent to `GetRegistry().FindAll(key)`.
 func FindAll(key string) []*registry.Network {
-	return getRegistryNetworksFull().FindAll(key)
+	return defaultRegistry.FindAll(key)
+}
+
+// FindByFirstStreamableBlock is a shortcut for [Registry.FindByFirstStreamableBlock] on the
+// default registry.
+func FindByFirstStreamableBlock(blockNum uint64, blockID string) *registry.Network {
+	return defaultRegistry.FindByFirstStreamableBlock(blockNum, blockID)
+}
+
+// FindBySubstreamsEndpoint is a shortcut for [Registry.FindBySubstreamsEndpoint] on the default
+// registry.
+func FindBySubstreamsEndpoint(endpoint string) *registry.Network {
+	return defaultRegistry.FindBySubstreamsEndpoint(endpoint)
 }
 
 // Search is a shortcut for [NetworkRegistry.Search] which
 // is equivalent to `GetRegistry().Search(re)`.
 func Search(re *regexp.Regexp) []*registry.Network {
-	return getRegistryNetworksFull().Search(re)
+	return defaultRegistry.Search(re)
 }
 
-// GetSubstreamsEndpoint returns the preferred Substreams endpoint for a given network key,
-// prioritizing streamingfast.io endpoints when available.
+// GetSubstreamsEndpoint is a shortcut for [Registry.SubstreamsEndpoint] on the
+// default registry.
 func GetSubstreamsEndpoint(key string) string {
-	network := Find(key)
-	if network == nil || len(network.Services.Substreams) == 0 {
-		return ""
-	}
-
-	// First, look for streamingfast.io endpoints
-	for _, endpoint := range network.Services.Substreams {
-		if strings.Contains(endpoint, "streamingfast.io") {
-			return endpoint
-		}
-	}
-
-	// If no streamingfast.io endpoint found, return the first available endpoint
-	return network.Services.Substreams[0]
+	return defaultRegistry.SubstreamsEndpoint(key)
 }
 
-// GetFirehoseEndpoint returns the preferred Firehose endpoint for a given network key,
-// prioritizing streamingfast.io endpoints when available.
+// GetFirehoseEndpoint is a shortcut for [Registry.FirehoseEndpoint] on the
+// default registry.
 func GetFirehoseEndpoint(key string) string {
-	network := Find(key)
-	if network == nil || len(network.Services.Firehose) == 0 {
+	return defaultRegistry.FirehoseEndpoint(key)
+}
+
+// preferredEndpoint returns the first streamingfast.io endpoint of endpoints if any,
+// the first endpoint otherwise and an empty string if there is none.
+func preferredEndpoint(endpoints []string) string {
+	if len(endpoints) == 0 {
 		return ""
 	}
 
 	// First, look for streamingfast.io endpoints
-	for _, endpoint := range network.Services.Firehose {
+	for _, endpoint := range endpoints {
 		if strings.Contains(endpoint, "streamingfast.io") {
 			return endpoint
 		}
 	}
 
 	// If no streamingfast.io endpoint found, return the first available endpoint
-	return network.Services.Firehose[0]
+	return endpoints[0]
 }
 
 // Returns the bytes encoding for a given network
@@ -307,44 +286,21 @@ func GetBytesEncoding(network *registry.Network) registry.BytesEncoding {
 	return registry.Hex
 }
 
-// FindBySubstreamsEndpoint returns the *registry.Network whose Substreams endpoint matches the given endpoint.
+// FindBySubstreamsEndpoint returns the *registry.Network whose Substreams endpoint matches the
+// given endpoint, the network with the lowest ID when several match.
 func (r NetworkRegistry) FindBySubstreamsEndpoint(endpoint string) *registry.Network {
-	for _, net := range r {
-		if slices.Contains(net.Services.Substreams, endpoint) {
-			return net
+	for _, id := range slices.Sorted(maps.Keys(r)) {
+		if network := r[id]; slices.Contains(network.Services.Substreams, endpoint) {
+			return network
 		}
 	}
-	return nil
-}
-
-var withInfiniteRetries = backoff.WithMaxTries(0)
 
-func backgroundUpdateLatestRegistry(ctx context.Context) {
-	operation := func() (NetworkRegistry, error) {
-		return loadRegistry(registry.FromLatestVersion)
-	}
-
-	registry, err := backoff.Retry(ctx, operation, withInfiniteRetries, backoff.WithBackOff(backoff.NewExponentialBackOff()))
-	if err != nil {
-		// We have been cancelled, nothing to do more
-		return
-	}
-
-	setRegistries(registry)
-}
-
-func setRegistries(source NetworkRegistry) {
-	// We could have used a atomic pointer here, but it's not a big deal,
-	// the on the fly update is not expected to be frequent and shouldn't cause
-	// any real issues as they are separated instances.
-	registryNetworksFull = source
-	registryNetworksFirehose = source.Filter(isFirehoseNetwork)
-	registryNetworksSubstreams = source.Filter(isSubstreamsNetwork)
+	return nil
 }
 
 // ScheduleUpdateLatestRegistry schedules a background update goroutine of the latest registry at the
-// specified interval. It runs in a goroutine and updates the global registryNetworks variable. You
-// can control it with a context to stop the updates gracefully.
+// specified interval. It runs in a goroutine and updates the default registry. You can control it
+// with a context to stop the updates gracefully.
 //
 // If you don't want any logging, pass nil as the logger parameter.
 func ScheduleUpdateLatestRegistry(ctx context.Context, interval time.Duration, logger *zap.Logger) {
@@ -352,26 +308,5 @@ func ScheduleUpdateLatestRegistry(ctx context.Context, interval time.Duration, l
 		logger = zap.NewNop()
 	}
 
-	go func() {
-		ticker := time.NewTicker(interval)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ctx.Done():
-				// Exit if context is cancelled
-				logger.Debug("stopping background registry update due to context cancellation")
-				return
-
-			case <-ticker.C:
-				registry, err := loadRegistry(registry.FromLatestVersion)
-				if err != nil {
-					logger.Info("failed to load latest registry, skipping this interval update", zap.Error(err))
-					continue
-				}
-
-				registryNetworksFull = registry
-			}
-		}
-	}()
+	defaultRegistry.scheduleUpdateLatestRegistry(ctx, interval, logger)
 }
@@ -1,6 +1,7 @@
 package networks
 
 import (
+	"context"
 	"regexp"
 	"testing"
 
@@ -201,7 +202,7 @@ func TestGetFirehoseRegistry(t *testing.T) {
 }
 
 func TestNetworkRegistry_FindByGenesisBlock(t *testing.T) {
-	networks := getRegistryNetworksFull()
+	networks := GetRegistry()
 	const moonbeamID = "moonbeam"
 	const moonbeamGenesisHash = "0x7e6b3bbed86828a558271c9c9f62354b1d8b5aa15ff85fd6f1e7cbe9af9dde7e"
 	const moonbeamGenesisHeight = 0
@@ -216,7 +217,7 @@ func TestNetworkRegistry_FindByGenesisBlock(t *testing.T) {
 }
 
 func TestGetBytesEncoding(t *testing.T) {
-	networks := getRegistryNetworksFull()
+	networks := GetRegistry()
 
 	t.Run("returns correct encoding for mainnet", func(t *testing.T) {
 		net := networks.Find("mainnet")
@@ -305,12 +306,9 @@ func TestGetSubstreamsEndpoint(t *testing.T) {
 			},
 		}
 
-		// Temporarily add to registry
-		reg := getRegistryNetworksFull()
-		reg["test-no-sf"] = net
-		defer delete(reg, "test-no-sf")
+		reg := New(WithSources(EmbeddedSource()), WithNetworks(net))
 
-		endpoint := GetSubstreamsEndpoint("test-no-sf")
+		endpoint := reg.SubstreamsEndpoint("test-no-sf")
 		assert.Equal(t, "test.pinax.network:443", endpoint)
 	})
 
@@ -328,12 +326,9 @@ func TestGetSubstreamsEndpoint(t *testing.T) {
 			},
 		}
 
-		// Temporarily add to registry
-		reg := getRegistryNetworksFull()
-		reg["test-no-substreams"] = net
-		defer delete(reg, "test-no-substreams")
+		reg := New(WithSources(EmbeddedSource()), WithNetworks(net))
 
-		endpoint := GetSubstreamsEndpoint("test-no-substreams")
+		endpoint := reg.SubstreamsEndpoint("test-no-substreams")
 		assert.Empty(t, endpoint)
 	})
 }
@@ -358,12 +353,9 @@ func TestGetFirehoseEndpoint(t *testing.T) {
 			},
 		}
 
-		// Temporarily add to registry
-		reg := getRegistryNetworksFull()
-		reg["test-no-sf"] = net
-		defer delete(reg, "test-no-sf")
+		reg := New(WithSources(EmbeddedSource()), WithNetworks(net))
 
-		endpoint := GetFirehoseEndpoint("test-no-sf")
+		endpoint := reg.FirehoseEndpoint("test-no-sf")
 		assert.Equal(t, "test.pinax.network:443", endpoint)
 	})
 
@@ -381,12 +373,9 @@ func TestGetFirehoseEndpoint(t *testing.T) {
 			},
 		}
 
-		// Temporarily add to registry
-		reg := getRegistryNetworksFull()
-		reg["test-no-firehose"] = net
-		defer delete(reg, "test-no-firehose")
+		reg := New(WithSources(EmbeddedSource()), WithNetworks(net))
 
-		endpoint := GetFirehoseEndpoint("test-no-firehose")
+		endpoint := reg.FirehoseEndpoint("test-no-firehose")
 		assert.Empty(t, endpoint)
 	})
 }
@@ -406,7 +395,7 @@ func TestNetworkRegistry_addServiceEndpoints(t *testing.T) {
 
 	t.Run("prepends endpoints to an existing network", func(t *testing.T) {
 		r := newRegistry()
-		r.addServiceEndpoints(&serviceOverride{
+		r.addServiceEndpoints(&ServiceOverride{
 			NetworkID:  "hoodi",
 			Firehose:   []string{"hoodi.eth.streamingfast.io:443"},
 			Substreams: []string{"hoodi.eth.streamingfast.io:443"},
@@ -418,7 +407,7 @@ func TestNetworkRegistry_addServiceEndpoints(t *testing.T) {
 
 	t.Run("does not duplicate endpoints already in the registry", func(t *testing.T) {
 		r := newRegistry()
-		override := &serviceOverride{
+		override := &ServiceOverride{
 			NetworkID:  "hoodi",
 			Firehose:   []string{"hoodi.firehose.pinax.network:443"},
 			Substreams: []string{"hoodi.substreams.pinax.network:443"},
@@ -433,7 +422,7 @@ func TestNetworkRegistry_addServiceEndpoints(t *testing.T) {
 
 	t.Run("leaves untouched services the override does not define", func(t *testing.T) {
 		r := newRegistry()
-		r.addServiceEndpoints(&serviceOverride{
+		r.addServiceEndpoints(&ServiceOverride{
 			NetworkID: "hoodi",
 			Firehose:  []string{"hoodi.eth.streamingfast.io:443"},
 		})
@@ -444,8 +433,8 @@ func TestNetworkRegistry_addServiceEndpoints(t *testing.T) {
 
 	t.Run("ignores unknown network and invalid input", func(t *testing.T) {
 		r := newRegistry()
-		r.addServiceEndpoints(&serviceOverride{NetworkID: "unknown-network", Firehose: []string{"unknown.streamingfast.io:443"}})
-		r.addServiceEndpoints(&serviceOverride{Firehose: []string{"unknown.streamingfast.io:443"}})
+		r.addServiceEndpoints(&ServiceOverride{NetworkID: "unknown-network", Firehose: []string{"unknown.streamingfast.io:443"}})
+		r.addServiceEndpoints(&ServiceOverride{Firehose: []string{"unknown.streamingfast.io:443"}})
 		r.addServiceEndpoints(nil)
 
 		assert.Equal(t, newRegistry(), r)
@@ -454,10 +443,10 @@ func TestNetworkRegistry_addServiceEndpoints(t *testing.T) {
 
 func TestServiceOverrides_Hoodi(t *testing.T) {
 	// Loaded from the embedded JSON so the assertions are not affected by the live registry
-	reg, err := loadRegistry(fromEmbeddedJSON)
+	snap, err := New(WithSources(EmbeddedSource())).loadSnapshot(context.Background(), 0)
 	require.NoError(t, err)
 
-	net := reg.Find("hoodi")
+	net := snap.full.Find("hoodi")
 	require.NotNil(t, net, "Network %q should be present in the registry", "hoodi")
 
 	assert.Equal(t, []string{"hoodi.eth.streamingfast.io:443", "hoodi.firehose.pinax.network:443"}, net.Services.Firehose)
@@ -0,0 +1,34 @@
+package networks
+
+import (
+	"strings"
+	"unicode"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+)
+
+// NormalizeKey returns the form of key used by [Registry.FindNormalized]: lowercase, with
+// dashes, underscores and spaces all turned into single dashes and trimmed from both ends, so
+// "Ethereum Mainnet", " ethereum_mainnet " and "ETHEREUM--MAINNET" all become "ethereum-mainnet".
+func NormalizeKey(key string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(key), isKeySeparator), "-")
+}
+
+func isKeySeparator(r rune) bool {
+	return r == '-' || r == '_' || unicode.IsSpace(r)
+}
+
+// FindNormalized is like [Registry.Find] for keys typed by users, like CLI flags or the network
+// of a manifest: when nothing matches key exactly, it's matched against IDs then aliases, full
+// names, short names and CAIP-2 IDs with both sides normalized by [NormalizeKey].
+//
+// Strict callers, where a key not matching exactly is an error, should use [Registry.Find].
+func (r *Registry) FindNormalized(key string) *registry.Network {
+	snap := r.snapshot()
+	return snap.index.findNormalized(snap.full, key)
+}
+
+// FindNormalized is a shortcut for [Registry.FindNormalized] on the default registry.
+func FindNormalized(key string) *registry.Network {
+	return defaultRegistry.FindNormalized(key)
+}
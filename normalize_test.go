@@ -0,0 +1,55 @@
+package networks
+
+import (
+	"testing"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeKey(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{"mainnet", "mainnet"},
+		{"Ethereum Mainnet", "ethereum-mainnet"},
+		{" ETH-Mainnet ", "eth-mainnet"},
+		{"arbitrum_one", "arbitrum-one"},
+		{"-- base \t sepolia__", "base-sepolia"},
+		{"   ", ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, NormalizeKey(test.key), "NormalizeKey(%q)", test.key)
+	}
+}
+
+func TestRegistry_FindNormalized(t *testing.T) {
+	t.Run("embedded", func(t *testing.T) {
+		r := loadEmbeddedRegistry(t)
+
+		for _, key := range []string{"ethereum mainnet", "ETH-Mainnet", " eth ", "EVM_1"} {
+			network := r.FindNormalized(key)
+			require.NotNil(t, network, "FindNormalized(%q)", key)
+			assert.Equal(t, "mainnet", network.ID, "FindNormalized(%q)", key)
+		}
+
+		assert.Nil(t, r.Find("ethereum mainnet"), "Find stays strict")
+		assert.Nil(t, r.FindNormalized("ethereum classic mainnet net"))
+		assert.Nil(t, r.FindNormalized(" "))
+	})
+
+	t.Run("precedence", func(t *testing.T) {
+		r := New(WithSources(staticSource(
+			registry.Network{ID: "alpha", FullName: "Beta Chain"},
+			registry.Network{ID: "beta-chain", FullName: "Other"},
+			registry.Network{ID: "gamma", FullName: "Gamma"},
+		)))
+
+		assert.Equal(t, "alpha", r.FindNormalized("Beta Chain").ID, "exact full name wins over normalized ID")
+		assert.Equal(t, "beta-chain", r.FindNormalized("beta chain").ID, "normalized ID wins over normalized full name")
+		assert.Equal(t, "gamma", r.FindNormalized("GAMMA ").ID)
+	})
+}
@@ -0,0 +1,238 @@
+package networks
+
+import (
+	"slices"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+)
+
+// ProvenanceKind classifies where a network or an endpoint comes from, see [Registry.Origin].
+type ProvenanceKind string
+
+const (
+	// ProvenanceRegistry is the registry document, loaded from one of the sources.
+	ProvenanceRegistry ProvenanceKind = "registry"
+	// ProvenanceBuiltin is an override compiled in this module, see overrides.go.
+	ProvenanceBuiltin ProvenanceKind = "builtin"
+	// ProvenanceCode is an override given in code through an option like [WithNetworks].
+	ProvenanceCode ProvenanceKind = "code"
+	// ProvenanceFile is an override read from an overrides file, see [LoadOverridesFile].
+	ProvenanceFile ProvenanceKind = "file"
+	// ProvenanceRuntime is a registration, see [Registry.RegisterNetwork].
+	ProvenanceRuntime ProvenanceKind = "runtime"
+)
+
+// Provenance describes where a network, an endpoint or a patch comes from.
+type Provenance struct {
+	Kind ProvenanceKind `json:"kind"`
+
+	// Source is the name of the [Source] for the registry document, the file path for overrides
+	// files and the option or function name for overrides given in code or at runtime.
+	Source string `json:"source,omitempty"`
+
+	// Name identifies the override: the network ID of custom networks, the name of service
+	// overrides and patches when they have one. Empty for the registry document.
+	Name string `json:"name,omitempty"`
+
+	// Version is the version of the registry document, empty for overrides.
+	Version string `json:"version,omitempty"`
+}
+
+func (p Provenance) String() string {
+	out := string(p.Kind)
+	if p.Source != "" {
+		out += " " + p.Source
+	}
+	if p.Version != "" {
+		out += "@" + p.Version
+	}
+	if p.Name != "" {
+		out += " (" + p.Name + ")"
+	}
+
+	return out
+}
+
+// NetworkOrigin explains where a network of the registry in use and each of its endpoints come
+// from, see [Registry.Origin].
+type NetworkOrigin struct {
+	NetworkID string `json:"network"`
+
+	// Network is the provenance of the network definition.
+	Network Provenance `json:"origin"`
+
+	Firehose   []EndpointOrigin `json:"firehose,omitempty"`
+	Substreams []EndpointOrigin `json:"substreams,omitempty"`
+
+	// Patches are the provenance of the patches that changed the network, in order.
+	Patches []Provenance `json:"patches,omitempty"`
+}
+
+// EndpointOrigin is the provenance of a single endpoint.
+type EndpointOrigin struct {
+	Endpoint string     `json:"endpoint"`
+	Origin   Provenance `json:"origin"`
+}
+
+// Origin explains where the network matching key, like [Registry.Find], and each of its endpoints
+// come from: the registry document, a built-in override, an overrides file or a registration. It
+// returns nil when no network matches.
+func (r *Registry) Origin(key string) *NetworkOrigin {
+	snap := r.snapshot()
+	network := snap.index.find(snap.full, key)
+	if network == nil {
+		return nil
+	}
+
+	tracked := snap.provenance[network.ID]
+	if tracked == nil {
+		tracked = &networkProvenance{origin: snap.registryProvenance()}
+	}
+
+	return &NetworkOrigin{
+		NetworkID:  network.ID,
+		Network:    tracked.origin,
+		Firehose:   tracked.endpointOrigins(network.Services.Firehose, tracked.firehose),
+		Substreams: tracked.endpointOrigins(network.Services.Substreams, tracked.substreams),
+		Patches:    slices.Clone(tracked.patches),
+	}
+}
+
+// Origin is a shortcut for [Registry.Origin] on the default registry.
+func Origin(key string) *NetworkOrigin {
+	return defaultRegistry.Origin(key)
+}
+
+// customNetwork, customService and customPatch are the overrides of a [Registry] along with
+// their provenance.
+type customNetwork struct {
+	network *registry.Network
+	forced  bool
+	origin  Provenance
+}
+
+type customService struct {
+	override *ServiceOverride
+	origin   Provenance
+}
+
+type customPatch struct {
+	patch  *NetworkPatch
+	origin Provenance
+}
+
+func customNetworks(origin Provenance, networks ...*registry.Network) (out []*customNetwork) {
+	for _, network := range networks {
+		if network != nil {
+			named := origin
+			named.Name = network.ID
+			out = append(out, &customNetwork{network: network, origin: named})
+		}
+	}
+
+	return out
+}
+
+func customServices(origin Provenance, overrides ...*ServiceOverride) (out []*customService) {
+	for _, override := range overrides {
+		if override != nil {
+			named := origin
+			named.Name = override.Name
+			out = append(out, &customService{override: override, origin: named})
+		}
+	}
+
+	return out
+}
+
+func customPatches(origin Provenance, patches ...*NetworkPatch) (out []*customPatch) {
+	for _, patch := range patches {
+		if patch != nil {
+			named := origin
+			named.Name = patch.Name
+			out = append(out, &customPatch{patch: patch, origin: named})
+		}
+	}
+
+	return out
+}
+
+// networkProvenance tracks the provenance of a network touched by an override while building a
+// snapshot, endpoints missing from the maps come from the same place as the network.
+type networkProvenance struct {
+	origin     Provenance
+	firehose   map[string]Provenance
+	substreams map[string]Provenance
+	patches    []Provenance
+}
+
+func (p *networkProvenance) endpointOrigins(endpoints []string, origins map[string]Provenance) []EndpointOrigin {
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	out := make([]EndpointOrigin, len(endpoints))
+	for i, endpoint := range endpoints {
+		origin, found := origins[endpoint]
+		if !found {
+			origin = p.origin
+		}
+
+		out[i] = EndpointOrigin{Endpoint: endpoint, Origin: origin}
+	}
+
+	return out
+}
+
+// provenanceTracker records the provenance of the networks touched by overrides while building
+// a snapshot.
+type provenanceTracker struct {
+	registry Provenance
+	networks map[string]*networkProvenance
+}
+
+func newProvenanceTracker(registry Provenance) *provenanceTracker {
+	return &provenanceTracker{registry: registry, networks: make(map[string]*networkProvenance)}
+}
+
+func (t *provenanceTracker) get(id string) *networkProvenance {
+	tracked, found := t.networks[id]
+	if !found {
+		tracked = &networkProvenance{origin: t.registry}
+		t.networks[id] = tracked
+	}
+
+	return tracked
+}
+
+// network records that the network with id was defined by origin, replacing anything before.
+func (t *provenanceTracker) network(id string, origin Provenance) {
+	t.networks[id] = &networkProvenance{origin: origin}
+}
+
+// endpoints records the endpoints that are in after but not in before as coming from origin.
+func (t *provenanceTracker) endpoints(id string, before, after registry.Services, origin Provenance) {
+	tracked := t.get(id)
+	tracked.firehose = trackNewEndpoints(tracked.firehose, before.Firehose, after.Firehose, origin)
+	tracked.substreams = trackNewEndpoints(tracked.substreams, before.Substreams, after.Substreams, origin)
+}
+
+func (t *provenanceTracker) patch(id string, origin Provenance) {
+	tracked := t.get(id)
+	tracked.patches = append(tracked.patches, origin)
+}
+
+func trackNewEndpoints(origins map[string]Provenance, before, after []string, origin Provenance) map[string]Provenance {
+	for _, endpoint := range after {
+		if slices.Contains(before, endpoint) {
+			continue
+		}
+
+		if origins == nil {
+			origins = make(map[string]Provenance)
+		}
+		origins[endpoint] = origin
+	}
+
+	return origins
+}
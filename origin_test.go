@@ -0,0 +1,71 @@
+package networks
+
+import (
+	"context"
+	"testing"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvenance_String(t *testing.T) {
+	assert.Equal(t, "registry embedded@0.7.34", Provenance{Kind: ProvenanceRegistry, Source: "embedded", Version: "0.7.34"}.String())
+	assert.Equal(t, "builtin (hoodiStreamingFast)", Provenance{Kind: ProvenanceBuiltin, Name: "hoodiStreamingFast"}.String())
+	assert.Equal(t, "file /etc/overrides.yaml (acme)", Provenance{Kind: ProvenanceFile, Source: "/etc/overrides.yaml", Name: "acme"}.String())
+}
+
+func TestRegistry_Origin(t *testing.T) {
+	t.Run("registry and builtin", func(t *testing.T) {
+		r := New(WithSources(EmbeddedSource()))
+		require.NoError(t, r.Load(context.Background()))
+
+		upstream := Provenance{Kind: ProvenanceRegistry, Source: "embedded", Version: r.Status().Version}
+		builtin := Provenance{Kind: ProvenanceBuiltin, Name: "hoodiStreamingFast"}
+
+		origin := r.Origin("hoodi")
+		require.NotNil(t, origin)
+		assert.Equal(t, "hoodi", origin.NetworkID)
+		assert.Equal(t, upstream, origin.Network)
+		assert.Contains(t, origin.Firehose, EndpointOrigin{Endpoint: "hoodi.eth.streamingfast.io:443", Origin: builtin})
+		assert.Contains(t, origin.Substreams, EndpointOrigin{Endpoint: "hoodi.eth.streamingfast.io:443", Origin: builtin})
+		for _, endpoint := range origin.Firehose {
+			if endpoint.Endpoint != "hoodi.eth.streamingfast.io:443" {
+				assert.Equal(t, upstream, endpoint.Origin)
+			}
+		}
+
+		origin = r.Origin("acme-dummy-blockchain")
+		require.NotNil(t, origin)
+		assert.Equal(t, Provenance{Kind: ProvenanceBuiltin, Name: "acme-dummy-blockchain"}, origin.Network)
+
+		assert.Equal(t, upstream, r.Origin("mainnet").Network)
+		assert.Nil(t, r.Origin("unknown"))
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		path := writeOverridesFile(t, t.TempDir(), "overrides.yaml", testOverridesYAML)
+
+		r := New(
+			WithSources(staticSource(registry.Network{ID: "alpha", Services: registry.Services{Firehose: []string{"alpha:443"}}})),
+			WithNetworks(testNetwork("code")),
+			WithOverridesFile(path),
+			WithPatches(&NetworkPatch{Name: "extra", NetworkID: "alpha", Firehose: &ListPatch{Add: []string{"alpha.extra:443"}}}),
+		)
+		require.NoError(t, r.Load(context.Background()))
+		require.NoError(t, r.RegisterNetwork(testNetwork("runtime")))
+
+		assert.Equal(t, Provenance{Kind: ProvenanceCode, Source: "WithNetworks", Name: "code"}, r.Origin("code").Network)
+		assert.Equal(t, Provenance{Kind: ProvenanceFile, Source: path, Name: "acme-devnet"}, r.Origin("acme").Network)
+		assert.Equal(t, Provenance{Kind: ProvenanceRuntime, Source: "RegisterNetwork", Name: "runtime"}, r.Origin("runtime").Network)
+
+		patch := Provenance{Kind: ProvenanceCode, Source: "WithPatches", Name: "extra"}
+		origin := r.Origin("alpha")
+		assert.Equal(t, []EndpointOrigin{
+			{Endpoint: "alpha.internal:443", Origin: Provenance{Kind: ProvenanceFile, Source: path}},
+			{Endpoint: "alpha:443", Origin: origin.Network},
+			{Endpoint: "alpha.extra:443", Origin: patch},
+		}, origin.Firehose)
+		assert.Equal(t, []Provenance{patch}, origin.Patches)
+	})
+}
@@ -0,0 +1,118 @@
+package networks
+
+import (
+	"slices"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+)
+
+// OverrideKind is the kind of override an [OverrideReport] is about.
+type OverrideKind string
+
+const (
+	// OverrideNetwork is a custom network, see [WithNetworks].
+	OverrideNetwork OverrideKind = "network"
+	// OverrideService is a [ServiceOverride].
+	OverrideService OverrideKind = "service"
+	// OverridePatch is a [NetworkPatch].
+	OverridePatch OverrideKind = "patch"
+)
+
+// OverrideStatus classifies an override against the registry in use, see [Registry.OverrideReports].
+type OverrideStatus string
+
+const (
+	// OverrideEffective is an override that changes the registry.
+	OverrideEffective OverrideStatus = "effective"
+	// OverrideRedundant is an override whose changes are already part of the registry, like a
+	// custom network the registry now lists or service endpoints it now has. It can be dropped.
+	OverrideRedundant OverrideStatus = "redundant"
+	// OverrideOrphaned is an override targeting a network that is not part of the registry,
+	// likely renamed or removed upstream. It has no effect.
+	OverrideOrphaned OverrideStatus = "orphaned"
+)
+
+// OverrideReport is the outcome of a single override on the registry in use.
+type OverrideReport struct {
+	Kind      OverrideKind   `json:"kind"`
+	NetworkID string         `json:"network"`
+	Origin    Provenance     `json:"origin"`
+	Status    OverrideStatus `json:"status"`
+
+	// RedundantFirehose and RedundantSubstreams are the endpoints of a service override the
+	// network already had, they can be removed from the override even when it's still effective.
+	RedundantFirehose   []string `json:"redundantFirehose,omitempty"`
+	RedundantSubstreams []string `json:"redundantSubstreams,omitempty"`
+}
+
+// Stale returns true when the override has no effect and can be dropped.
+func (r OverrideReport) Stale() bool {
+	return r.Status != OverrideEffective
+}
+
+// OverrideReports classifies each override (built-in, given in code, read from overrides files
+// or registered at runtime) as effective, redundant or orphaned against the registry in use, in
+// the order they are applied: custom networks, service overrides then patches.
+//
+// Since the registry catching up is what makes an override redundant, it's meant to be checked
+// when upgrading the registry, to prune overrides that are no longer needed.
+func (r *Registry) OverrideReports() []OverrideReport {
+	return slices.Clone(r.snapshot().overrideReports)
+}
+
+// OverrideReports is a shortcut for [Registry.OverrideReports] on the default registry.
+func OverrideReports() []OverrideReport {
+	return defaultRegistry.OverrideReports()
+}
+
+func networkOverrideReport(custom *customNetwork, added bool) OverrideReport {
+	report := OverrideReport{Kind: OverrideNetwork, NetworkID: custom.network.ID, Origin: custom.origin, Status: OverrideEffective}
+	if !added {
+		report.Status = OverrideRedundant
+	}
+
+	return report
+}
+
+// serviceOverrideReport classifies custom against network, the network before the override is
+// applied, nil when the registry doesn't have it.
+func serviceOverrideReport(custom *customService, network *registry.Network) OverrideReport {
+	report := OverrideReport{Kind: OverrideService, NetworkID: custom.override.NetworkID, Origin: custom.origin, Status: OverrideOrphaned}
+	if network == nil {
+		return report
+	}
+
+	report.RedundantFirehose = redundantEndpoints(custom.override.Firehose, network.Services.Firehose)
+	report.RedundantSubstreams = redundantEndpoints(custom.override.Substreams, network.Services.Substreams)
+
+	report.Status = OverrideEffective
+	if len(report.RedundantFirehose) == len(custom.override.Firehose) && len(report.RedundantSubstreams) == len(custom.override.Substreams) {
+		report.Status = OverrideRedundant
+	}
+
+	return report
+}
+
+func redundantEndpoints(endpoints, existing []string) (out []string) {
+	for _, endpoint := range endpoints {
+		if slices.Contains(existing, endpoint) {
+			out = append(out, endpoint)
+		}
+	}
+
+	return out
+}
+
+func patchOverrideReport(custom *customPatch, status PatchStatus) OverrideReport {
+	report := OverrideReport{Kind: OverridePatch, NetworkID: custom.patch.NetworkID, Origin: custom.origin}
+	switch status {
+	case PatchApplied:
+		report.Status = OverrideEffective
+	case PatchNoop:
+		report.Status = OverrideRedundant
+	default:
+		report.Status = OverrideOrphaned
+	}
+
+	return report
+}
@@ -0,0 +1,79 @@
+package networks
+
+import (
+	"context"
+	"testing"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assertOverridesEffective fails for each report that is stale, to catch overrides to prune.
+func assertOverridesEffective(t testing.TB, reports []OverrideReport) {
+	t.Helper()
+
+	for _, report := range reports {
+		assert.False(t, report.Stale(), "%s override %s of network %q is %s, it can be removed", report.Kind, report.Origin, report.NetworkID, report.Status)
+		assert.Empty(t, report.RedundantFirehose, "%s override %s of network %q has Firehose endpoints already in the registry", report.Kind, report.Origin, report.NetworkID)
+		assert.Empty(t, report.RedundantSubstreams, "%s override %s of network %q has Substreams endpoints already in the registry", report.Kind, report.Origin, report.NetworkID)
+	}
+}
+
+// TestBuiltinOverrides fails when the embedded registry caught up with an override of
+// overrides.go, meaning it should be removed while bumping the fallback registry.
+func TestBuiltinOverrides(t *testing.T) {
+	r := New(WithSources(EmbeddedSource()))
+	require.NoError(t, r.Load(context.Background()))
+
+	var builtin []OverrideReport
+	for _, report := range r.OverrideReports() {
+		if report.Origin.Kind == ProvenanceBuiltin {
+			builtin = append(builtin, report)
+		}
+	}
+
+	assert.Len(t, builtin, len(networkOverrides)+len(serviceOverrides))
+	assertOverridesEffective(t, builtin)
+}
+
+func TestRegistry_OverrideReports(t *testing.T) {
+	alpha := registry.Network{ID: "alpha", Services: registry.Services{Firehose: []string{"alpha:443"}, Substreams: []string{"alpha:443"}}}
+	r := New(
+		WithSources(staticSource(alpha)),
+		WithNetworks(testNetwork("custom"), testNetwork("alpha")),
+		WithOverrides(&Overrides{Services: []*ServiceOverride{
+			{Name: "partial", NetworkID: "alpha", Firehose: []string{"alpha:443", "alpha.internal:443"}},
+			{Name: "caught-up", NetworkID: "alpha", Substreams: []string{"alpha:443"}},
+			{Name: "renamed", NetworkID: "beta", Firehose: []string{"beta:443"}},
+		}}),
+		WithPatches(
+			&NetworkPatch{Name: "noop", NetworkID: "alpha", Firehose: &ListPatch{Add: []string{"alpha:443"}}},
+			&NetworkPatch{Name: "gone", NetworkID: "gamma", FullName: new(string)},
+		),
+	)
+	require.NoError(t, r.Load(context.Background()))
+
+	var reports []OverrideReport
+	for _, report := range r.OverrideReports() {
+		if report.Origin.Kind != ProvenanceBuiltin {
+			reports = append(reports, report)
+		}
+	}
+
+	overrides := Provenance{Kind: ProvenanceCode, Source: "WithOverrides"}
+	named := func(origin Provenance, name string) Provenance {
+		origin.Name = name
+		return origin
+	}
+
+	assert.Equal(t, []OverrideReport{
+		{Kind: OverrideNetwork, NetworkID: "custom", Origin: Provenance{Kind: ProvenanceCode, Source: "WithNetworks", Name: "custom"}, Status: OverrideEffective},
+		{Kind: OverrideNetwork, NetworkID: "alpha", Origin: Provenance{Kind: ProvenanceCode, Source: "WithNetworks", Name: "alpha"}, Status: OverrideRedundant},
+		{Kind: OverrideService, NetworkID: "alpha", Origin: named(overrides, "partial"), Status: OverrideEffective, RedundantFirehose: []string{"alpha:443"}},
+		{Kind: OverrideService, NetworkID: "alpha", Origin: named(overrides, "caught-up"), Status: OverrideRedundant, RedundantSubstreams: []string{"alpha:443"}},
+		{Kind: OverrideService, NetworkID: "beta", Origin: named(overrides, "renamed"), Status: OverrideOrphaned},
+		{Kind: OverridePatch, NetworkID: "alpha", Origin: Provenance{Kind: ProvenanceCode, Source: "WithPatches", Name: "noop"}, Status: OverrideRedundant},
+		{Kind: OverridePatch, NetworkID: "gamma", Origin: Provenance{Kind: ProvenanceCode, Source: "WithPatches", Name: "gone"}, Status: OverrideOrphaned},
+	}, reports)
+}
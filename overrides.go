@@ -7,32 +7,38 @@ var (
 		ACMEDummyBlockchain,
 	}
 
-	serviceOverrides = []*serviceOverride{
+	serviceOverrides = []*ServiceOverride{
 		hoodiStreamingFast,
 	}
 )
 
-// serviceOverride adds service endpoints to a network that already exists in the official
-// registry, which [networkOverrides] cannot do since it only adds networks that are missing.
+// ServiceOverride adds service endpoints to a network that already exists in the official
+// registry, which custom networks cannot do since they only add networks that are missing.
 //
 // It is meant to declare StreamingFast endpoints for networks the registry doesn't list them
 // for yet. Endpoints are merged in front of the ones coming from the registry and duplicates
-// are dropped, so an override turns into a no-op once the registry catches up.
-type serviceOverride struct {
-	// NetworkID is the [registry.Network.ID] of the network to augment, an unknown ID is ignored.
-	NetworkID string
+// are dropped, so an override turns into a no-op once the registry catches up, which
+// [Registry.OverrideReports] reports as [OverrideRedundant].
+type ServiceOverride struct {
+	// Name optionally identifies the override in provenance reports, see [Registry.Origin].
+	Name string `json:"name,omitempty"`
+
+	// NetworkID is the [registry.Network.ID] of the network to augment, an unknown ID is ignored
+	// and reported as [OverrideOrphaned].
+	NetworkID string `json:"network"`
 
 	// Firehose endpoints to add to [registry.Services.Firehose].
-	Firehose []string
+	Firehose []string `json:"firehose,omitempty"`
 
 	// Substreams endpoints to add to [registry.Services.Substreams].
-	Substreams []string
+	Substreams []string `json:"substreams,omitempty"`
 }
 
 var (
 	// StreamingFast endpoints for the Ethereum Hoodi testnet, the registry only knows about
 	// the Pinax ones for now.
-	hoodiStreamingFast = &serviceOverride{
+	hoodiStreamingFast = &ServiceOverride{
+		Name:       "hoodiStreamingFast",
 		NetworkID:  "hoodi",
 		Firehose:   []string{"hoodi.eth.streamingfast.io:443"},
 		Substreams: []string{"hoodi.eth.streamingfast.io:443"},
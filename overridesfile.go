@@ -0,0 +1,182 @@
+package networks
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// OverridesEnvVar is the environment variable holding the paths of overrides files applied to
+// every [Registry], separated like the PATH (`:` on Unix), see [LoadOverridesFile]. It's read
+// on the first load of each registry, like the files themselves, see [WithOverridesFile].
+const OverridesEnvVar = "FIREHOSE_NETWORKS_OVERRIDES"
+
+// overridesFileNames are the file names looked up in each directory of an overrides search path.
+var overridesFileNames = []string{"firehose-networks.yaml", "firehose-networks.yml", "firehose-networks.json"}
+
+// Overrides are custom networks, service endpoints and patches merged into the registry document,
+// with the same rules as the built-in ones: a custom network is only added when the registry
+// doesn't know its ID, service endpoints are merged in front of the registry ones and patches are
+// applied last, see [NetworkPatch].
+//
+// In a file, see [LoadOverridesFile], networks use the fields of the registry document:
+//
+//	networks:
+//	  - id: acme-devnet
+//	    fullName: Acme Devnet
+//	    shortName: Acme
+//	    caip2Id: acme:devnet
+//	    networkType: devnet
+//	    services:
+//	      firehose: [acme-devnet.internal:443]
+//	services:
+//	  - network: hoodi
+//	    firehose: [hoodi.internal:443]
+//	patches:
+//	  - network: mainnet
+//	    firehose:
+//	      remove: [dead.endpoint.io:443]
+type Overrides struct {
+	Networks []*registry.Network `json:"networks,omitempty"`
+	Services []*ServiceOverride  `json:"services,omitempty"`
+	Patches  []*NetworkPatch     `json:"patches,omitempty"`
+}
+
+// LoadOverridesFile reads the overrides file at path, YAML unless its extension is `.json`.
+// Unknown fields are rejected to catch typos.
+func LoadOverridesFile(path string) (*Overrides, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read overrides file: %w", err)
+	}
+
+	if !strings.EqualFold(filepath.Ext(path), ".json") {
+		if content, err = yamlToJSON(content); err != nil {
+			return nil, fmt.Errorf("parse overrides file %s: %w", path, err)
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+
+	overrides := &Overrides{}
+	if err := decoder.Decode(overrides); err != nil {
+		return nil, fmt.Errorf("parse overrides file %s: %w", path, err)
+	}
+
+	return overrides, nil
+}
+
+// yamlToJSON converts a YAML document to JSON so it's decoded with the JSON field names of the
+// registry types.
+func yamlToJSON(content []byte) ([]byte, error) {
+	var document any
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+
+	if document == nil {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(document)
+}
+
+// WithOverrides merges overrides into the registry, after the built-in ones and the ones given
+// before. When several custom networks share an ID, the first one wins.
+//
+// Overrides files, see [WithOverridesFile], [WithOverridesSearchPath] and [OverridesEnvVar],
+// are merged after all the overrides given in code, in this order.
+func WithOverrides(overrides *Overrides) Option {
+	return func(r *Registry) {
+		r.addOverrides(overrides, Provenance{Kind: ProvenanceCode, Source: "WithOverrides"})
+	}
+}
+
+// WithOverridesFile merges the overrides file at path into the registry, see [LoadOverridesFile].
+// The file is read on the first load of the registry. When it cannot be loaded, it's skipped with
+// a warning and the registry loads without it, see [Registry.Load].
+func WithOverridesFile(path string) Option {
+	return func(r *Registry) {
+		r.overridesFiles = append(r.overridesFiles, func() string { return path })
+	}
+}
+
+// WithOverridesSearchPath merges the first overrides file found in dirs, looking in each of them
+// for `firehose-networks.yaml`, `firehose-networks.yml` or `firehose-networks.json`. Like
+// [WithOverridesFile], dirs are searched on the first load of the registry. Finding no file is not
+// an error.
+func WithOverridesSearchPath(dirs ...string) Option {
+	return func(r *Registry) {
+		r.overridesFiles = append(r.overridesFiles, func() string {
+			for _, dir := range dirs {
+				for _, name := range overridesFileNames {
+					path := filepath.Join(dir, name)
+					if _, err := os.Stat(path); err == nil {
+						return path
+					}
+				}
+			}
+
+			return ""
+		})
+	}
+}
+
+func (r *Registry) addOverrides(overrides *Overrides, origin Provenance) {
+	if overrides == nil {
+		return
+	}
+
+	r.networkOverrides = append(r.networkOverrides, customNetworks(origin, overrides.Networks...)...)
+	r.serviceOverrides = append(r.serviceOverrides, customServices(origin, overrides.Services...)...)
+	r.patches = append(r.patches, customPatches(origin, overrides.Patches...)...)
+}
+
+func (r *Registry) addOverridesFile(path string) error {
+	overrides, err := LoadOverridesFile(path)
+	if err != nil {
+		r.logger.Warn("skipped invalid registry overrides file", zap.String("path", path), zap.Error(err))
+		return err
+	}
+
+	r.logger.Info("loaded registry overrides file", zap.String("path", path), zap.Int("networks", len(overrides.Networks)), zap.Int("services", len(overrides.Services)), zap.Int("patches", len(overrides.Patches)))
+	r.addOverrides(overrides, Provenance{Kind: ProvenanceFile, Source: path})
+
+	return nil
+}
+
+// addOverridesFiles merges the overrides files given through options followed by the ones listed
+// in [OverridesEnvVar], returning why some were skipped. It runs once, on the first load, before
+// any snapshot is built.
+func (r *Registry) addOverridesFiles() error {
+	var paths []string
+	for _, resolve := range r.overridesFiles {
+		paths = append(paths, resolve())
+	}
+
+	var errs []error
+	for _, path := range append(paths, filepath.SplitList(os.Getenv(OverridesEnvVar))...) {
+		if path != "" {
+			if err := r.addOverridesFile(path); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	err := fmt.Errorf("skipped invalid overrides files: %w", errors.Join(errs...))
+	r.refreshStatus.recordOverrides(err)
+	return err
+}
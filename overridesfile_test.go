@@ -0,0 +1,187 @@
+package networks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOverridesYAML = `
+networks:
+  - id: acme-devnet
+    fullName: Acme Devnet
+    shortName: Acme
+    caip2Id: acme:devnet
+    networkType: devnet
+    aliases: [acme]
+    services:
+      firehose: [acme-devnet.internal:443]
+    firehose:
+      blockType: sf.acme.type.v1.Block
+      bytesEncoding: hex
+      firstStreamableBlock:
+        id: "0x00"
+        height: 0
+services:
+  - network: alpha
+    firehose: [alpha.internal:443]
+`
+
+const testOverridesJSON = `{
+	"networks": [{"id": "json-devnet", "fullName": "JSON Devnet", "shortName": "JSON", "caip2Id": "json:devnet", "networkType": "devnet", "services": {}}],
+	"services": [{"network": "alpha", "substreams": ["alpha.internal:443"]}]
+}`
+
+func writeOverridesFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	return path
+}
+
+func TestLoadOverridesFile(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("yaml", func(t *testing.T) {
+		overrides, err := LoadOverridesFile(writeOverridesFile(t, dir, "overrides.yaml", testOverridesYAML))
+		require.NoError(t, err)
+
+		require.Len(t, overrides.Networks, 1)
+		network := overrides.Networks[0]
+		assert.Equal(t, "acme-devnet", network.ID)
+		assert.Equal(t, registry.Devnet, network.NetworkType)
+		assert.Equal(t, []string{"acme"}, network.Aliases)
+		assert.Equal(t, []string{"acme-devnet.internal:443"}, network.Services.Firehose)
+		require.NotNil(t, network.Firehose)
+		assert.Equal(t, "0x00", network.Firehose.FirstStreamableBlock.ID)
+
+		assert.Equal(t, []*ServiceOverride{{NetworkID: "alpha", Firehose: []string{"alpha.internal:443"}}}, overrides.Services)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		overrides, err := LoadOverridesFile(writeOverridesFile(t, dir, "overrides.json", testOverridesJSON))
+		require.NoError(t, err)
+
+		require.Len(t, overrides.Networks, 1)
+		assert.Equal(t, "json-devnet", overrides.Networks[0].ID)
+		assert.Equal(t, []*ServiceOverride{{NetworkID: "alpha", Substreams: []string{"alpha.internal:443"}}}, overrides.Services)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		overrides, err := LoadOverridesFile(writeOverridesFile(t, dir, "empty.yaml", ""))
+		require.NoError(t, err)
+		assert.Empty(t, overrides.Networks)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := LoadOverridesFile(writeOverridesFile(t, dir, "typo.yaml", "service:\n  - network: alpha\n"))
+		assert.ErrorContains(t, err, `unknown field "service"`)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := LoadOverridesFile(filepath.Join(dir, "missing.yaml"))
+		assert.ErrorContains(t, err, "read overrides file")
+	})
+}
+
+func TestRegistry_Overrides(t *testing.T) {
+	source := staticSource(registry.Network{ID: "alpha", Services: registry.Services{Firehose: []string{"alpha:443"}}})
+
+	t.Run("file", func(t *testing.T) {
+		path := writeOverridesFile(t, t.TempDir(), "overrides.yaml", testOverridesYAML)
+		r := New(WithSources(source), WithOverridesFile(path))
+
+		assert.True(t, r.Has("acme"))
+		assert.Equal(t, []string{"alpha.internal:443", "alpha:443"}, r.Find("alpha").Services.Firehose)
+	})
+
+	t.Run("search path", func(t *testing.T) {
+		first, second := t.TempDir(), t.TempDir()
+		writeOverridesFile(t, second, "firehose-networks.json", testOverridesJSON)
+		writeOverridesFile(t, second, "firehose-networks.yml", testOverridesYAML)
+
+		r := New(WithSources(source), WithOverridesSearchPath(first, second))
+
+		// The YAML file takes precedence in the directory, only the first file found is used
+		assert.True(t, r.Has("acme-devnet"))
+		assert.False(t, r.Has("json-devnet"))
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv(OverridesEnvVar, writeOverridesFile(t, dir, "a.yaml", testOverridesYAML)+string(os.PathListSeparator)+writeOverridesFile(t, dir, "b.json", testOverridesJSON))
+
+		r := New(WithSources(source))
+		assert.True(t, r.Has("acme-devnet"))
+		assert.True(t, r.Has("json-devnet"))
+		assert.Equal(t, []string{"alpha.internal:443"}, r.Find("alpha").Services.Substreams)
+	})
+
+	t.Run("code overrides take precedence over files", func(t *testing.T) {
+		path := writeOverridesFile(t, t.TempDir(), "overrides.yaml", testOverridesYAML)
+		r := New(
+			WithSources(source),
+			WithOverridesFile(path),
+			WithOverrides(&Overrides{Networks: []*registry.Network{{ID: "acme-devnet", FullName: "From Code"}}}),
+		)
+
+		assert.Equal(t, "From Code", r.Find("acme-devnet").FullName)
+	})
+
+	t.Run("registry networks take precedence", func(t *testing.T) {
+		r := New(WithSources(source), WithOverrides(&Overrides{Networks: []*registry.Network{{ID: "alpha", FullName: "Custom"}}}))
+
+		assert.Empty(t, r.Find("alpha").FullName)
+	})
+
+	t.Run("invalid file is skipped", func(t *testing.T) {
+		dir := t.TempDir()
+		invalid := writeOverridesFile(t, dir, "invalid.yaml", "networks: {")
+		valid := writeOverridesFile(t, dir, "valid.yaml", testOverridesYAML)
+		r := New(WithSources(source), WithOverridesFile(invalid), WithOverridesFile(valid))
+
+		err := r.Load(context.Background())
+		assert.ErrorContains(t, err, "skipped invalid overrides files: parse overrides file "+invalid)
+		assert.NoError(t, r.Load(context.Background()), "only the load reading the files reports them")
+		assert.NotPanics(t, func() { r.MustLoad(context.Background()) })
+
+		assert.True(t, r.Has("alpha"))
+		assert.True(t, r.Has("acme-devnet"))
+
+		status := r.Status()
+		assert.ErrorContains(t, status.OverridesError, "parse overrides file "+invalid)
+		assert.NoError(t, status.LastError)
+		assert.False(t, status.Retrying)
+	})
+
+	t.Run("invalid file doesn't make MustLoad panic", func(t *testing.T) {
+		invalid := writeOverridesFile(t, t.TempDir(), "invalid.yaml", "networks: {")
+		r := New(WithSources(source), WithOverridesFile(invalid))
+
+		assert.NotPanics(t, func() { r.MustLoad(context.Background()) })
+		assert.True(t, r.Has("alpha"))
+	})
+
+	t.Run("invalid environment file is reported by the implicit load", func(t *testing.T) {
+		t.Setenv(OverridesEnvVar, filepath.Join(t.TempDir(), "missing.yaml"))
+
+		r := New(WithSources(source))
+		assert.True(t, r.Has("alpha"))
+		assert.ErrorContains(t, r.Status().OverridesError, "read overrides file")
+	})
+
+	t.Run("files are read on first load", func(t *testing.T) {
+		dir := t.TempDir()
+		r := New(WithSources(source), WithOverridesSearchPath(dir))
+
+		writeOverridesFile(t, dir, "firehose-networks.yaml", testOverridesYAML)
+		assert.True(t, r.Has("acme-devnet"))
+	})
+}
@@ -0,0 +1,169 @@
+package networks
+
+import (
+	"slices"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+)
+
+// NetworkPatch fixes fields of a network of the registry before upstream does, like a wrong first
+// streamable block or a dead endpoint. Patches are applied to every loaded registry after the
+// custom networks and the service overrides, only the fields set are changed.
+//
+// A patch whose changes are all already part of the registry is reported as [PatchNoop] by
+// [Registry.PatchResults], meaning it can be dropped since upstream caught up.
+type NetworkPatch struct {
+	// Name optionally identifies the patch in provenance reports, see [Registry.Origin].
+	Name string `json:"name,omitempty"`
+
+	// NetworkID is the [registry.Network.ID] of the network to patch.
+	NetworkID string `json:"network"`
+
+	FullName  *string    `json:"fullName,omitempty"`
+	ShortName *string    `json:"shortName,omitempty"`
+	Aliases   *ListPatch `json:"aliases,omitempty"`
+
+	// Firehose and Substreams patch the endpoints of [registry.Services].
+	Firehose   *ListPatch `json:"firehose,omitempty"`
+	Substreams *ListPatch `json:"substreams,omitempty"`
+
+	// BlockType, BytesEncoding and FirstStreamableBlock patch [registry.Network.Firehose], created
+	// when the network has none.
+	BlockType            *string                        `json:"blockType,omitempty"`
+	BytesEncoding        *registry.BytesEncoding        `json:"bytesEncoding,omitempty"`
+	FirstStreamableBlock *registry.FirstStreamableBlock `json:"firstStreamableBlock,omitempty"`
+}
+
+// ListPatch changes a list of strings: the list is first replaced by Replace when it's not nil,
+// then Add elements missing from it are appended and Remove elements are removed from it.
+type ListPatch struct {
+	Replace []string `json:"replace,omitempty"`
+	Add     []string `json:"add,omitempty"`
+	Remove  []string `json:"remove,omitempty"`
+}
+
+// apply returns the patched list, in is never modified.
+func (p *ListPatch) apply(in []string) []string {
+	if p == nil {
+		return in
+	}
+
+	out := slices.Clone(in)
+	if p.Replace != nil {
+		out = slices.Clone(p.Replace)
+	}
+
+	for _, element := range p.Add {
+		if !slices.Contains(out, element) {
+			out = append(out, element)
+		}
+	}
+
+	return slices.DeleteFunc(out, func(element string) bool {
+		return slices.Contains(p.Remove, element)
+	})
+}
+
+// PatchStatus is the outcome of applying a [NetworkPatch] to a loaded registry.
+type PatchStatus string
+
+const (
+	// PatchApplied is a patch that changed its network.
+	PatchApplied PatchStatus = "applied"
+	// PatchNoop is a patch whose changes are all already part of the registry.
+	PatchNoop PatchStatus = "noop"
+	// PatchUnknownNetwork is a patch targeting a network that is not part of the registry.
+	PatchUnknownNetwork PatchStatus = "unknown-network"
+)
+
+// PatchResult is the outcome of a [NetworkPatch], see [Registry.PatchResults].
+type PatchResult struct {
+	Patch  *NetworkPatch `json:"patch"`
+	Status PatchStatus   `json:"status"`
+}
+
+// WithPatches applies patches to every loaded registry, in order.
+func WithPatches(patches ...*NetworkPatch) Option {
+	return func(r *Registry) {
+		r.patches = append(r.patches, customPatches(Provenance{Kind: ProvenanceCode, Source: "WithPatches"}, patches...)...)
+	}
+}
+
+// PatchResults reports the outcome of each patch on the registry in use, in the order patches
+// are applied.
+func (r *Registry) PatchResults() []PatchResult {
+	return slices.Clone(r.snapshot().patchResults)
+}
+
+// applyPatch applies patch to the network it targets. The network is copied before being
+// patched, so networks shared with other snapshots or with the loaded registry document are
+// never modified.
+func (r NetworkRegistry) applyPatch(patch *NetworkPatch) PatchStatus {
+	network, found := r[patch.NetworkID]
+	if !found {
+		return PatchUnknownNetwork
+	}
+
+	patched := *network
+	if patch.FullName != nil {
+		patched.FullName = *patch.FullName
+	}
+	if patch.ShortName != nil {
+		patched.ShortName = *patch.ShortName
+	}
+	patched.Aliases = patch.Aliases.apply(network.Aliases)
+	patched.Services.Firehose = patch.Firehose.apply(network.Services.Firehose)
+	patched.Services.Substreams = patch.Substreams.apply(network.Services.Substreams)
+
+	if patch.BlockType != nil || patch.BytesEncoding != nil || patch.FirstStreamableBlock != nil {
+		firehose := registry.Firehose{}
+		if network.Firehose != nil {
+			firehose = *network.Firehose
+		}
+
+		if patch.BlockType != nil {
+			firehose.BlockType = *patch.BlockType
+		}
+		if patch.BytesEncoding != nil {
+			firehose.BytesEncoding = *patch.BytesEncoding
+		}
+		if patch.FirstStreamableBlock != nil {
+			block := *patch.FirstStreamableBlock
+			firehose.FirstStreamableBlock = &block
+		}
+
+		patched.Firehose = &firehose
+	}
+
+	if !patchChanged(network, &patched) {
+		return PatchNoop
+	}
+
+	r[patch.NetworkID] = &patched
+	return PatchApplied
+}
+
+// patchChanged compares the fields a [NetworkPatch] can change.
+func patchChanged(before, after *registry.Network) bool {
+	if before.FullName != after.FullName || before.ShortName != after.ShortName ||
+		!slices.Equal(before.Aliases, after.Aliases) ||
+		!slices.Equal(before.Services.Firehose, after.Services.Firehose) ||
+		!slices.Equal(before.Services.Substreams, after.Services.Substreams) {
+		return true
+	}
+
+	if before.Firehose == nil || after.Firehose == nil {
+		return before.Firehose != after.Firehose
+	}
+
+	if before.Firehose.BlockType != after.Firehose.BlockType || before.Firehose.BytesEncoding != after.Firehose.BytesEncoding {
+		return true
+	}
+
+	beforeBlock, afterBlock := before.Firehose.FirstStreamableBlock, after.Firehose.FirstStreamableBlock
+	if beforeBlock == nil || afterBlock == nil {
+		return beforeBlock != afterBlock
+	}
+
+	return *beforeBlock != *afterBlock
+}
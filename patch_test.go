@@ -0,0 +1,150 @@
+package networks
+
+import (
+	"testing"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListPatch(t *testing.T) {
+	in := []string{"a", "b", "c"}
+
+	tests := []struct {
+		name     string
+		patch    *ListPatch
+		expected []string
+	}{
+		{"nil", nil, []string{"a", "b", "c"}},
+		{"add", &ListPatch{Add: []string{"d", "a"}}, []string{"a", "b", "c", "d"}},
+		{"remove", &ListPatch{Remove: []string{"b", "z"}}, []string{"a", "c"}},
+		{"replace", &ListPatch{Replace: []string{"x"}}, []string{"x"}},
+		{"replace then add and remove", &ListPatch{Replace: []string{"x", "y"}, Add: []string{"z"}, Remove: []string{"x"}}, []string{"y", "z"}},
+		{"clear", &ListPatch{Replace: []string{}}, []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.patch.apply(in))
+			assert.Equal(t, []string{"a", "b", "c"}, in)
+		})
+	}
+}
+
+func TestNetworkRegistry_applyPatch(t *testing.T) {
+	original := &registry.Network{
+		ID:       "alpha",
+		Aliases:  []string{"a"},
+		Services: registry.Services{Firehose: []string{"dead:443", "alpha:443"}},
+		Firehose: &registry.Firehose{
+			BytesEncoding:        registry.Hex,
+			FirstStreamableBlock: &registry.FirstStreamableBlock{Height: 0, ID: "0x00"},
+		},
+	}
+
+	t.Run("applied", func(t *testing.T) {
+		r := NetworkRegistry{"alpha": original}
+		status := r.applyPatch(&NetworkPatch{
+			NetworkID:            "alpha",
+			FullName:             ptr("Alpha"),
+			Aliases:              &ListPatch{Add: []string{"alpha-mainnet"}},
+			Firehose:             &ListPatch{Remove: []string{"dead:443"}},
+			BytesEncoding:        ptr(registry.The0Xhex),
+			FirstStreamableBlock: &registry.FirstStreamableBlock{Height: 10, ID: "0x0a"},
+		})
+
+		assert.Equal(t, PatchApplied, status)
+		patched := r["alpha"]
+		assert.Equal(t, "Alpha", patched.FullName)
+		assert.Equal(t, []string{"a", "alpha-mainnet"}, patched.Aliases)
+		assert.Equal(t, []string{"alpha:443"}, patched.Services.Firehose)
+		assert.Equal(t, registry.The0Xhex, patched.Firehose.BytesEncoding)
+		assert.Equal(t, int64(10), patched.Firehose.FirstStreamableBlock.Height)
+
+		// The patched network is a copy
+		assert.Empty(t, original.FullName)
+		assert.Equal(t, []string{"dead:443", "alpha:443"}, original.Services.Firehose)
+		assert.Equal(t, registry.Hex, original.Firehose.BytesEncoding)
+		assert.Equal(t, int64(0), original.Firehose.FirstStreamableBlock.Height)
+	})
+
+	t.Run("noop once the registry caught up", func(t *testing.T) {
+		r := NetworkRegistry{"alpha": original}
+		status := r.applyPatch(&NetworkPatch{
+			NetworkID:            "alpha",
+			Aliases:              &ListPatch{Add: []string{"a"}},
+			Substreams:           &ListPatch{Remove: []string{"dead:443"}},
+			BytesEncoding:        ptr(registry.Hex),
+			FirstStreamableBlock: &registry.FirstStreamableBlock{Height: 0, ID: "0x00"},
+		})
+
+		assert.Equal(t, PatchNoop, status)
+		assert.Same(t, original, r["alpha"])
+	})
+
+	t.Run("creates the firehose information", func(t *testing.T) {
+		r := NetworkRegistry{"beta": {ID: "beta"}}
+
+		assert.Equal(t, PatchApplied, r.applyPatch(&NetworkPatch{NetworkID: "beta", BlockType: ptr("sf.beta.type.v1.Block")}))
+		assert.Equal(t, "sf.beta.type.v1.Block", r["beta"].Firehose.BlockType)
+	})
+
+	t.Run("unknown network", func(t *testing.T) {
+		r := NetworkRegistry{"alpha": original}
+
+		assert.Equal(t, PatchUnknownNetwork, r.applyPatch(&NetworkPatch{NetworkID: "unknown", FullName: ptr("Unknown")}))
+	})
+}
+
+func TestRegistry_WithPatches(t *testing.T) {
+	source := staticSource(registry.Network{ID: "alpha", Services: registry.Services{Firehose: []string{"dead:443", "alpha:443"}}})
+
+	patches := []*NetworkPatch{
+		{NetworkID: "alpha", Firehose: &ListPatch{Remove: []string{"dead:443"}}},
+		{NetworkID: "alpha", Substreams: &ListPatch{Remove: []string{"dead:443"}}},
+		{NetworkID: "custom", Aliases: &ListPatch{Add: []string{"my-custom"}}},
+		{NetworkID: "gone", FullName: ptr("Gone")},
+	}
+
+	r := New(
+		WithSources(source),
+		WithNetworks(&registry.Network{ID: "custom"}),
+		WithOverrides(&Overrides{Services: []*ServiceOverride{{NetworkID: "alpha", Firehose: []string{"dead:443"}}}}),
+		WithPatches(patches...),
+	)
+
+	// Patches are applied after the service overrides and apply to custom networks too
+	assert.Equal(t, []string{"alpha:443"}, r.Find("alpha").Services.Firehose)
+	assert.True(t, r.Has("my-custom"))
+
+	results := r.PatchResults()
+	require.Len(t, results, 4)
+	for i, status := range []PatchStatus{PatchApplied, PatchNoop, PatchApplied, PatchUnknownNetwork} {
+		assert.Same(t, patches[i], results[i].Patch)
+		assert.Equal(t, status, results[i].Status, patches[i].NetworkID)
+	}
+}
+
+func TestLoadOverridesFile_Patches(t *testing.T) {
+	path := writeOverridesFile(t, t.TempDir(), "overrides.yaml", `
+patches:
+  - network: mainnet
+    bytesEncoding: 0xhex
+    firstStreamableBlock:
+      id: "0x0a"
+      height: 10
+    firehose:
+      remove: [dead.endpoint.io:443]
+`)
+
+	overrides, err := LoadOverridesFile(path)
+	require.NoError(t, err)
+	require.Len(t, overrides.Patches, 1)
+
+	patch := overrides.Patches[0]
+	assert.Equal(t, "mainnet", patch.NetworkID)
+	assert.Equal(t, registry.The0Xhex, *patch.BytesEncoding)
+	assert.Equal(t, &registry.FirstStreamableBlock{Height: 10, ID: "0x0a"}, patch.FirstStreamableBlock)
+	assert.Equal(t, &ListPatch{Remove: []string{"dead.endpoint.io:443"}}, patch.Firehose)
+}
@@ -0,0 +1,195 @@
+package networks
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+)
+
+// ErrNetworkExists is returned by [Registry.RegisterNetwork] when the network ID is already used
+// and [ForceReplace] is not given.
+var ErrNetworkExists = errors.New("network already exists")
+
+// ErrUnknownNetwork is matched by the [*UnknownNetworkError] returned when a network is not part
+// of the registry in use, like by [Registry.Lookup] or [Registry.RegisterServiceOverride].
+var ErrUnknownNetwork = errors.New("unknown network")
+
+var networkIDRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// RegisterOption configures [Registry.RegisterNetwork].
+type RegisterOption func(n *customNetwork)
+
+// ForceReplace registers the network even when the registry already has one with the same ID,
+// replacing it on every loaded registry.
+func ForceReplace() RegisterOption {
+	return func(n *customNetwork) {
+		n.forced = true
+	}
+}
+
+// registrations holds the networks and service overrides registered at runtime, generation is
+// incremented on each registration so snapshots built before it are rebuilt.
+type registrations struct {
+	lock       sync.Mutex
+	generation uint64
+	networks   []*customNetwork
+	services   []*customService
+}
+
+func (r *registrations) get() (networks []*customNetwork, services []*customService, generation uint64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.networks, r.services, r.generation
+}
+
+func (r *registrations) currentGeneration() uint64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.generation
+}
+
+// RegisterNetwork adds a custom network to the registry in use and to every registry loaded
+// afterwards, like background refreshes. Unless [ForceReplace] is given, registering a network
+// whose ID is already used fails with [ErrNetworkExists] and, when a later registry adds a network
+// with the same ID, the registry one wins like for [WithNetworks].
+//
+// The network is validated: it needs an ID made of lowercase letters, digits and dashes, a full
+// name, a valid CAIP-2 ID and network type, and well formed endpoints. It's copied, modifying it
+// afterwards has no effect.
+func (r *Registry) RegisterNetwork(network *registry.Network, opts ...RegisterOption) error {
+	// Copied first so the validated network is the one registered.
+	network = cloneNetwork(network)
+	if err := validateNetwork(network); err != nil {
+		return err
+	}
+
+	registered := &customNetwork{network: network, origin: Provenance{Kind: ProvenanceRuntime, Source: "RegisterNetwork", Name: network.ID}}
+	for _, opt := range opts {
+		opt(registered)
+	}
+
+	r.registrations.lock.Lock()
+	if !registered.forced {
+		exists := slices.ContainsFunc(r.registrations.networks, func(n *customNetwork) bool { return n.network.ID == network.ID })
+		if snap := r.current.Load(); snap != nil && snap.full[network.ID] != nil {
+			exists = true
+		}
+
+		if exists {
+			r.registrations.lock.Unlock()
+			return fmt.Errorf("register network %q: %w", network.ID, ErrNetworkExists)
+		}
+	}
+
+	r.registrations.networks = append(slices.Clip(r.registrations.networks), registered)
+	r.registrations.generation++
+	r.registrations.lock.Unlock()
+
+	r.reapplyOverrides()
+	return nil
+}
+
+// RegisterServiceOverride adds the endpoints of override to the registry in use and to every
+// registry loaded afterwards, with the same rules as the built-in service overrides. It fails with
+// [ErrUnknownNetwork] when the registry in use doesn't have the targeted network.
+func (r *Registry) RegisterServiceOverride(override *ServiceOverride) error {
+	if override == nil || override.NetworkID == "" {
+		return errors.New("register service override: network ID is required")
+	}
+
+	if len(override.Firehose) == 0 && len(override.Substreams) == 0 {
+		return fmt.Errorf("register service override for %q: no endpoint", override.NetworkID)
+	}
+
+	if err := validateEndpoints(slices.Concat(override.Firehose, override.Substreams)); err != nil {
+		return fmt.Errorf("register service override for %q: %w", override.NetworkID, err)
+	}
+
+	if snap := r.current.Load(); snap != nil && snap.loaded(r) && snap.full[override.NetworkID] == nil {
+		return fmt.Errorf("register service override: %w", r.unknownNetworkError(override.NetworkID))
+	}
+
+	registered := &customService{
+		override: &ServiceOverride{
+			Name:       override.Name,
+			NetworkID:  override.NetworkID,
+			Firehose:   slices.Clone(override.Firehose),
+			Substreams: slices.Clone(override.Substreams),
+		},
+		origin: Provenance{Kind: ProvenanceRuntime, Source: "RegisterServiceOverride", Name: override.Name},
+	}
+
+	r.registrations.lock.Lock()
+	r.registrations.services = append(slices.Clip(r.registrations.services), registered)
+	r.registrations.generation++
+	r.registrations.lock.Unlock()
+
+	r.reapplyOverrides()
+	return nil
+}
+
+// RegisterNetwork is a shortcut for [Registry.RegisterNetwork] on the default registry.
+func RegisterNetwork(network *registry.Network, opts ...RegisterOption) error {
+	return defaultRegistry.RegisterNetwork(network, opts...)
+}
+
+// RegisterServiceOverride is a shortcut for [Registry.RegisterServiceOverride] on the default
+// registry.
+func RegisterServiceOverride(override *ServiceOverride) error {
+	return defaultRegistry.RegisterServiceOverride(override)
+}
+
+func validateNetwork(network *registry.Network) error {
+	if network == nil {
+		return errors.New("register network: network is nil")
+	}
+
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("register network %q: %s", network.ID, fmt.Sprintf(format, args...))
+	}
+
+	if !networkIDRegex.MatchString(network.ID) {
+		return invalid("ID must be made of lowercase letters, digits and dashes")
+	}
+
+	if strings.TrimSpace(network.FullName) == "" {
+		return invalid("full name is required")
+	}
+
+	if _, err := ParseCAIP2(network.Caip2ID); err != nil {
+		return invalid("%s", err)
+	}
+
+	switch network.NetworkType {
+	case registry.Mainnet, registry.Testnet, registry.Devnet, registry.Beacon:
+	default:
+		return invalid("invalid network type %q", network.NetworkType)
+	}
+
+	if slices.Contains(network.Aliases, "") {
+		return invalid("empty alias")
+	}
+
+	if err := validateEndpoints(slices.Concat(network.Services.Firehose, network.Services.Substreams)); err != nil {
+		return invalid("%s", err)
+	}
+
+	return nil
+}
+
+func validateEndpoints(endpoints []string) error {
+	for _, endpoint := range endpoints {
+		if endpoint == "" || strings.ContainsAny(endpoint, " \t\n") {
+			return fmt.Errorf("invalid endpoint %q", endpoint)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package networks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testNetwork(id string) *registry.Network {
+	return &registry.Network{
+		ID:          id,
+		FullName:    "Test " + id,
+		ShortName:   id,
+		Caip2ID:     "test:" + id,
+		NetworkType: registry.Devnet,
+		Services:    registry.Services{Firehose: []string{id + ".internal:443"}},
+	}
+}
+
+// versionedSource returns a new registry version with the same networks on each load.
+func versionedSource(networks ...registry.Network) Source {
+	var calls atomic.Int64
+	return SourceFunc("versioned", func(ctx context.Context) (*registry.NetworksRegistry, error) {
+		native, err := staticSource(networks...).Load(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		native.Version = fmt.Sprintf("0.0.%d", calls.Add(1))
+		return native, nil
+	})
+}
+
+// refreshedPast returns true once r loaded the given version of a [versionedSource] or a later one,
+// refreshes are frequent enough in tests for versions to be skipped between checks.
+func refreshedPast(r *Registry, patch int) bool {
+	version, err := parseSemver(r.Status().Version)
+	return err == nil && version.patch >= patch
+}
+
+func TestRegistry_RegisterNetwork(t *testing.T) {
+	t.Run("validation", func(t *testing.T) {
+		r := New(WithSources(staticSource()))
+
+		tests := []struct {
+			name   string
+			modify func(n *registry.Network)
+			err    string
+		}{
+			{"id", func(n *registry.Network) { n.ID = "My Chain" }, "ID must be made of lowercase letters"},
+			{"full name", func(n *registry.Network) { n.FullName = " " }, "full name is required"},
+			{"caip2", func(n *registry.Network) { n.Caip2ID = "nope" }, `invalid CAIP-2 ID "nope"`},
+			{"network type", func(n *registry.Network) { n.NetworkType = "prodnet" }, `invalid network type "prodnet"`},
+			{"alias", func(n *registry.Network) { n.Aliases = []string{""} }, "empty alias"},
+			{"endpoint", func(n *registry.Network) { n.Services.Substreams = []string{"bad endpoint:443"} }, `invalid endpoint "bad endpoint:443"`},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				network := testNetwork("custom")
+				test.modify(network)
+
+				assert.ErrorContains(t, r.RegisterNetwork(network), test.err)
+			})
+		}
+
+		assert.ErrorContains(t, r.RegisterNetwork(nil), "network is nil")
+		assert.False(t, r.Has("custom"))
+	})
+
+	t.Run("survives refreshes", func(t *testing.T) {
+		r := New(WithSources(versionedSource(registry.Network{ID: "alpha"})))
+		require.NoError(t, r.Load(context.Background()))
+
+		network := testNetwork("custom")
+		require.NoError(t, r.RegisterNetwork(network))
+		network.FullName = "Modified"
+
+		assert.Equal(t, "Test custom", r.Find("custom").FullName)
+		assert.True(t, r.FirehoseNetworks().Has("custom"))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		r.ScheduleUpdateLatestRegistry(ctx, time.Millisecond)
+
+		assert.Eventually(t, func() bool { return refreshedPast(r, 3) }, 5*time.Second, time.Millisecond)
+		assert.True(t, r.Has("custom"))
+	})
+
+	t.Run("applied on first load", func(t *testing.T) {
+		r := New(WithSources(staticSource(registry.Network{ID: "alpha"})))
+		require.NoError(t, r.RegisterNetwork(testNetwork("custom")))
+
+		assert.True(t, r.Has("custom"))
+		assert.True(t, r.Has("alpha"))
+	})
+
+	t.Run("existing network", func(t *testing.T) {
+		r := New(WithSources(staticSource(registry.Network{ID: "alpha", FullName: "Alpha"})))
+		require.NoError(t, r.Load(context.Background()))
+
+		assert.ErrorIs(t, r.RegisterNetwork(testNetwork("alpha")), ErrNetworkExists)
+		require.NoError(t, r.RegisterNetwork(testNetwork("custom")))
+		assert.ErrorIs(t, r.RegisterNetwork(testNetwork("custom")), ErrNetworkExists)
+
+		require.NoError(t, r.RegisterNetwork(testNetwork("alpha"), ForceReplace()))
+		assert.Equal(t, "Test alpha", r.Find("alpha").FullName)
+	})
+
+	t.Run("modifying the network afterwards has no effect", func(t *testing.T) {
+		r := New(WithSources(staticSource()))
+		require.NoError(t, r.Load(context.Background()))
+
+		network := testNetwork("mine")
+		network.Aliases = []string{"mine-alias"}
+		network.Firehose = &registry.Firehose{BlockType: "sf.test.v1.Block"}
+		require.NoError(t, r.RegisterNetwork(network))
+
+		network.Aliases[0] = "changed"
+		network.Services.Firehose[0] = "b:443"
+		network.Firehose.BlockType = "sf.changed.v1.Block"
+
+		registered := r.Find("mine-alias")
+		require.NotNil(t, registered)
+		assert.Nil(t, r.Find("changed"))
+		assert.Equal(t, []string{"mine.internal:443"}, registered.Services.Firehose)
+		assert.Equal(t, "sf.test.v1.Block", registered.Firehose.BlockType)
+
+		require.NoError(t, r.RegisterNetwork(testNetwork("other")), "rebuilding the registry applies the registered copy")
+		assert.NotNil(t, r.Find("mine-alias"))
+		assert.Nil(t, r.Find("changed"))
+		assert.Equal(t, []string{"mine.internal:443"}, r.Find("mine").Services.Firehose)
+	})
+
+	t.Run("notifies subscribers", func(t *testing.T) {
+		r := New(WithSources(staticSource()))
+		require.NoError(t, r.Load(context.Background()))
+
+		var updates []Update
+		r.Subscribe(func(update Update) { updates = append(updates, update) })
+
+		require.NoError(t, r.RegisterNetwork(testNetwork("custom")))
+		require.Len(t, updates, 1)
+		require.Len(t, updates[0].Diff.Added, 1)
+		assert.Equal(t, "custom", updates[0].Diff.Added[0].ID)
+	})
+
+	t.Run("concurrent with refreshes", func(t *testing.T) {
+		r := New(WithSources(versionedSource(registry.Network{ID: "alpha"})))
+		require.NoError(t, r.Load(context.Background()))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		r.ScheduleUpdateLatestRegistry(ctx, time.Millisecond)
+
+		var wg sync.WaitGroup
+		for i := range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, r.RegisterNetwork(testNetwork(fmt.Sprintf("custom-%d", i))))
+			}()
+		}
+		wg.Wait()
+
+		for i := range 20 {
+			assert.True(t, r.Has(fmt.Sprintf("custom-%d", i)))
+		}
+	})
+}
+
+func TestRegistry_RegisterServiceOverride(t *testing.T) {
+	r := New(WithSources(versionedSource(registry.Network{ID: "alpha", Services: registry.Services{Firehose: []string{"alpha:443"}}})))
+	require.NoError(t, r.Load(context.Background()))
+
+	assert.ErrorContains(t, r.RegisterServiceOverride(&ServiceOverride{}), "network ID is required")
+	assert.ErrorContains(t, r.RegisterServiceOverride(&ServiceOverride{NetworkID: "alpha"}), "no endpoint")
+	assert.ErrorContains(t, r.RegisterServiceOverride(&ServiceOverride{NetworkID: "alpha", Firehose: []string{""}}), `invalid endpoint ""`)
+	assert.ErrorIs(t, r.RegisterServiceOverride(&ServiceOverride{NetworkID: "unknown", Firehose: []string{"unknown:443"}}), ErrUnknownNetwork)
+
+	require.NoError(t, r.RegisterServiceOverride(&ServiceOverride{NetworkID: "alpha", Firehose: []string{"alpha.internal:443"}}))
+	assert.Equal(t, []string{"alpha.internal:443", "alpha:443"}, r.Find("alpha").Services.Firehose)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r.ScheduleUpdateLatestRegistry(ctx, time.Millisecond)
+
+	assert.Eventually(t, func() bool { return refreshedPast(r, 3) }, 5*time.Second, time.Millisecond)
+	assert.Equal(t, []string{"alpha.internal:443", "alpha:443"}, r.Find("alpha").Services.Firehose)
+}
@@ -0,0 +1,238 @@
+package networks
+
+import (
+	"regexp"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// Registry is a self-contained network registry: it owns the sources used to load the
+// registry document, the overrides merged into it, the filtered Firehose and Substreams views
+// and the background refresh loop keeping all of them up to date.
+//
+// A Registry is created with [New] and loads lazily on first access. The package level functions
+// like [Find] or [GetFirehoseRegistry] are shortcuts over a default instance returned by [Default].
+type Registry struct {
+	sources          []Source
+	cacheDir         string
+	cacheIndex       int
+	networkOverrides []*customNetwork
+	serviceOverrides []*customService
+	patches          []*customPatch
+	overridesFiles   []func() string
+	loadTimeout      time.Duration
+	logger           *zap.Logger
+
+	verifiers         []Verifier
+	versionConstraint *versionConstraint
+	metricsRegisterer prometheus.Registerer
+	metrics           *metrics
+
+	// configErr holds the invalid options, it fails every load without trying the sources.
+	configErr error
+
+	// overridesOnce reads the overrides files on the first load.
+	overridesOnce sync.Once
+
+	loadLock      sync.Mutex
+	refreshLock   sync.Mutex
+	current       atomic.Pointer[snapshot]
+	registrations registrations
+	retrying      atomic.Bool
+	refreshStatus refreshStatus
+	subscribers   subscribers
+}
+
+// Option configures a [Registry] created through [New].
+type Option func(r *Registry)
+
+// WithSources sets the ordered chain of sources the registry document is loaded from, defaults
+// to [LatestSource] followed by [EmbeddedSource].
+//
+// Sources are tried in order and the first one that loads successfully is used. When it's not
+// the first source of the chain, the sources preceding it are retried in the background until
+// one succeeds. Refreshes only consider the source in use and the ones preceding it, a refresh
+// never replaces a registry with one coming from a less preferred source.
+func WithSources(sources ...Source) Option {
+	return func(r *Registry) {
+		r.sources = sources
+	}
+}
+
+// WithCacheDir enables the on-disk cache of the registry in dir, created if missing. Each
+// registry successfully loaded from a source preceding the cache is written to it, and the
+// cache is tried right before [EmbeddedSource] when it's the last source of the chain, last
+// otherwise. This keeps recent endpoints across restarts happening while the remote registry
+// is unavailable.
+func WithCacheDir(dir string) Option {
+	return func(r *Registry) {
+		r.cacheDir = dir
+	}
+}
+
+// WithNetworks adds custom networks to the registry on top of the built-in ones. Like the
+// built-in overrides, a network whose ID is already part of the registry document is ignored.
+func WithNetworks(networks ...*registry.Network) Option {
+	return func(r *Registry) {
+		r.networkOverrides = append(r.networkOverrides, customNetworks(Provenance{Kind: ProvenanceCode, Source: "WithNetworks"}, networks...)...)
+	}
+}
+
+// WithLoadTimeout bounds each load the registry performs on its own, defaults to 10 seconds. It
+// applies to the implicit load triggered by the first lookup when [Registry.Load] wasn't called,
+// to background retries and to scheduled refreshes. When loading from a source takes longer, it's
+// abandoned for the next one in the chain.
+func WithLoadTimeout(timeout time.Duration) Option {
+	return func(r *Registry) {
+		r.loadTimeout = timeout
+	}
+}
+
+// WithLogger sets the logger used by the registry, mostly for background refreshes. Defaults
+// to a no-op logger.
+func WithLogger(logger *zap.Logger) Option {
+	return func(r *Registry) {
+		if logger == nil {
+			logger = zap.NewNop()
+		}
+
+		r.logger = logger
+	}
+}
+
+// New creates a [Registry] configured through opts. Nothing is loaded until the registry is
+// first accessed.
+func New(opts ...Option) *Registry {
+	r := &Registry{
+		sources:          []Source{LatestSource(), EmbeddedSource()},
+		cacheIndex:       -1,
+		loadTimeout:      defaultLoadTimeout,
+		networkOverrides: customNetworks(Provenance{Kind: ProvenanceBuiltin}, networkOverrides...),
+		serviceOverrides: customServices(Provenance{Kind: ProvenanceBuiltin}, serviceOverrides...),
+		logger:           zap.NewNop(),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.configErr != nil {
+		r.logger.Error("invalid registry configuration, the registry will hold custom networks only", zap.Error(r.configErr))
+	}
+
+	if r.versionConstraint != nil {
+		if version, ok := r.versionConstraint.exact(); ok {
+			r.sources = slices.Clone(r.sources)
+			for i, source := range r.sources {
+				if url, ok := source.(*urlSource); ok && url.latest {
+					r.sources[i] = VersionSource(version.String())
+				}
+			}
+		}
+	}
+
+	if r.cacheDir != "" {
+		r.cacheIndex = len(r.sources)
+		if r.cacheIndex > 0 {
+			if _, ok := r.sources[r.cacheIndex-1].(embeddedSource); ok {
+				r.cacheIndex--
+			}
+		}
+
+		r.sources = slices.Insert(slices.Clone(r.sources), r.cacheIndex, Source(&cacheSource{dir: r.cacheDir}))
+	}
+
+	if r.metricsRegisterer != nil {
+		r.metrics = newMetrics(r, r.metricsRegisterer)
+	}
+
+	return r
+}
+
+var defaultRegistry = New()
+
+// Default returns the [Registry] backing the package level functions.
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Networks returns the full network registry without any filtering.
+//
+// The returned registry is shared and must not be modified, a refresh replaces it instead of
+// updating it in place.
+func (r *Registry) Networks() NetworkRegistry {
+	return r.snapshot().full
+}
+
+// SubstreamsNetworks returns only networks with Substreams endpoints, it always comes from the
+// same generation as [Registry.Networks].
+func (r *Registry) SubstreamsNetworks() NetworkRegistry {
+	return r.snapshot().substreams
+}
+
+// FirehoseNetworks returns only networks with Firehose endpoints, it always comes from the
+// same generation as [Registry.Networks].
+func (r *Registry) FirehoseNetworks() NetworkRegistry {
+	return r.snapshot().firehose
+}
+
+// Has is like [NetworkRegistry.Has] on [Registry.Networks].
+func (r *Registry) Has(key string) bool {
+	return r.Find(key) != nil
+}
+
+// Find is like [NetworkRegistry.Find] on [Registry.Networks].
+func (r *Registry) Find(key string) *registry.Network {
+	snap := r.snapshot()
+	return snap.index.find(snap.full, key)
+}
+
+// FindAll is like [NetworkRegistry.FindAll] on [Registry.Networks].
+func (r *Registry) FindAll(key string) []*registry.Network {
+	return r.snapshot().index.findAll(key)
+}
+
+// FindByFirstStreamableBlock is like [NetworkRegistry.FindByFirstStreamableBlock] on
+// [Registry.Networks], the network with the lowest ID being returned when several match.
+func (r *Registry) FindByFirstStreamableBlock(blockNum uint64, blockID string) *registry.Network {
+	return first(r.snapshot().index.byFirstStreamableBlock[firstStreamableBlock{blockNum, nox(blockID)}])
+}
+
+// FindBySubstreamsEndpoint is like [NetworkRegistry.FindBySubstreamsEndpoint] on
+// [Registry.Networks], the network with the lowest ID being returned when several match.
+func (r *Registry) FindBySubstreamsEndpoint(endpoint string) *registry.Network {
+	return first(r.snapshot().index.bySubstreamsEndpoint[endpoint])
+}
+
+// Search is a shortcut for [NetworkRegistry.Search] on [Registry.Networks].
+func (r *Registry) Search(re *regexp.Regexp) []*registry.Network {
+	return r.Networks().Search(re)
+}
+
+// SubstreamsEndpoint returns the preferred Substreams endpoint for a given network key,
+// prioritizing streamingfast.io endpoints when available.
+func (r *Registry) SubstreamsEndpoint(key string) string {
+	network := r.Find(key)
+	if network == nil {
+		return ""
+	}
+
+	return preferredEndpoint(network.Services.Substreams)
+}
+
+// FirehoseEndpoint returns the preferred Firehose endpoint for a given network key,
+// prioritizing streamingfast.io endpoints when available.
+func (r *Registry) FirehoseEndpoint(key string) string {
+	network := r.Find(key)
+	if network == nil {
+		return ""
+	}
+
+	return preferredEndpoint(network.Services.Firehose)
+}
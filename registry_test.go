@@ -0,0 +1,120 @@
+package networks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func staticSource(networks ...registry.Network) Source {
+	return SourceFunc("static", func(context.Context) (*registry.NetworksRegistry, error) {
+		// Each load must return fresh networks, like a real source parsing a document would
+		return &registry.NetworksRegistry{Version: "0.0.1", Networks: slices.Clone(networks)}, nil
+	})
+}
+
+var failingSource = SourceFunc("failing", func(context.Context) (*registry.NetworksRegistry, error) {
+	return nil, errors.New("registry unavailable")
+})
+
+func TestNew(t *testing.T) {
+	t.Run("uses the configured source", func(t *testing.T) {
+		r := New(WithSources(staticSource(registry.Network{ID: "custom", Services: registry.Services{Firehose: []string{"custom:443"}}})))
+
+		assert.NotNil(t, r.Find("custom"))
+		assert.Nil(t, r.Find("mainnet"))
+		assert.Equal(t, "custom:443", r.FirehoseEndpoint("custom"))
+		assert.Empty(t, r.SubstreamsEndpoint("custom"))
+		assert.Contains(t, r.FirehoseNetworks(), "custom")
+		assert.NotContains(t, r.SubstreamsNetworks(), "custom")
+	})
+
+	t.Run("falls back when a source fails", func(t *testing.T) {
+		r := New(WithSources(failingSource, staticSource(registry.Network{ID: "fallback"})))
+
+		assert.True(t, r.Has("fallback"))
+	})
+
+	t.Run("applies built-in overrides", func(t *testing.T) {
+		r := New(WithSources(EmbeddedSource()))
+
+		require.NotNil(t, r.Find(ACMEDummyBlockchain.ID))
+		assert.Equal(t, "hoodi.eth.streamingfast.io:443", r.FirehoseEndpoint("hoodi"))
+	})
+
+	t.Run("custom networks do not replace registry ones", func(t *testing.T) {
+		r := New(
+			WithSources(staticSource(registry.Network{ID: "custom", FullName: "From Registry"})),
+			WithNetworks(&registry.Network{ID: "custom", FullName: "From Option"}, &registry.Network{ID: "other"}),
+		)
+
+		assert.Equal(t, "From Registry", r.Find("custom").FullName)
+		assert.True(t, r.Has("other"))
+	})
+
+	t.Run("instances are isolated", func(t *testing.T) {
+		first := New(WithSources(staticSource()), WithNetworks(&registry.Network{ID: "first"}))
+		second := New(WithSources(staticSource()))
+
+		assert.True(t, first.Has("first"))
+		assert.False(t, second.Has("first"))
+		assert.False(t, Has("first"))
+	})
+}
+
+func TestRegistry_ScheduleUpdateLatestRegistry(t *testing.T) {
+	var calls atomic.Int64
+	source := SourceFunc("alternating", func(context.Context) (*registry.NetworksRegistry, error) {
+		version := calls.Add(1)
+
+		// Every other generation has Firehose endpoints, each view must always agree with the full one
+		services := registry.Services{Substreams: []string{"alpha:443"}}
+		if version%2 == 0 {
+			services.Firehose = []string{"alpha:443"}
+		}
+
+		return &registry.NetworksRegistry{
+			Version:  fmt.Sprintf("0.0.%d", version),
+			Networks: []registry.Network{{ID: "alpha", Services: services}},
+		}, nil
+	})
+
+	r := New(WithSources(source))
+	require.NotNil(t, r.Find("alpha"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r.ScheduleUpdateLatestRegistry(ctx, time.Millisecond)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for range 500 {
+				snap := r.snapshot()
+				_, inFirehose := snap.firehose["alpha"]
+				assert.Equal(t, len(snap.full["alpha"].Services.Firehose) > 0, inFirehose)
+				assert.NotNil(t, r.Find("alpha"))
+				assert.NotNil(t, r.SubstreamsNetworks().Find("alpha"))
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Firehose view must follow the refreshes and not stay stuck on the initial generation
+	assert.Eventually(t, func() bool {
+		return r.FirehoseNetworks().Has("alpha")
+	}, time.Second, time.Millisecond)
+}
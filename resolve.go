@@ -0,0 +1,128 @@
+package networks
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+)
+
+// ErrAmbiguousNetwork is matched by the [*AmbiguousNetworkError] returned by [Registry.Resolve].
+var ErrAmbiguousNetwork = errors.New("ambiguous network")
+
+// MatchField is a field of a network a key can match.
+type MatchField string
+
+const (
+	MatchID        MatchField = "id"
+	MatchAlias     MatchField = "alias"
+	MatchShortName MatchField = "shortName"
+	MatchFullName  MatchField = "fullName"
+	MatchCAIP2     MatchField = "caip2"
+)
+
+// NetworkMatch is a network matching a key, with the fields it matched.
+type NetworkMatch struct {
+	Network *registry.Network `json:"network"`
+	Fields  []MatchField      `json:"fields"`
+}
+
+// AmbiguousNetworkError is returned when a key matches several networks, listing all of them
+// sorted by ID. It matches [ErrAmbiguousNetwork] with [errors.Is].
+type AmbiguousNetworkError struct {
+	Key     string         `json:"key"`
+	Matches []NetworkMatch `json:"matches"`
+}
+
+func (e *AmbiguousNetworkError) Error() string {
+	matches := make([]string, len(e.Matches))
+	for i, match := range e.Matches {
+		fields := make([]string, len(match.Fields))
+		for j, field := range match.Fields {
+			fields[j] = string(field)
+		}
+
+		matches[i] = fmt.Sprintf("%q (%s)", match.Network.ID, strings.Join(fields, ", "))
+	}
+
+	return fmt.Sprintf("ambiguous network %q matches %s", e.Key, strings.Join(matches, ", "))
+}
+
+func (e *AmbiguousNetworkError) Unwrap() error {
+	return ErrAmbiguousNetwork
+}
+
+// Resolve is the strict counterpart of [Registry.Find]: it returns the network matching key by
+// ID, alias, short name, full name or CAIP-2 ID, an [*UnknownNetworkError] when there is none
+// and an [*AmbiguousNetworkError] when there are several, even when one of them matches by ID.
+// Keys aren't normalized.
+func (r *Registry) Resolve(key string) (*registry.Network, error) {
+	snap := r.snapshot()
+
+	networks := snap.index.byKey[key]
+	switch len(networks) {
+	case 0:
+		return nil, r.unknownNetworkError(key)
+	case 1:
+		return networks[0], nil
+	default:
+		return nil, newAmbiguousNetworkError(key, networks)
+	}
+}
+
+// Resolve is a shortcut for [Registry.Resolve] on the default registry.
+func Resolve(key string) (*registry.Network, error) {
+	return defaultRegistry.Resolve(key)
+}
+
+// AmbiguousKeys reports every key of the registry in use that [Registry.Resolve] rejects as
+// ambiguous, sorted by key.
+func (r *Registry) AmbiguousKeys() []*AmbiguousNetworkError {
+	byKey := r.snapshot().index.byKey
+
+	var out []*AmbiguousNetworkError
+	for _, key := range slices.Sorted(maps.Keys(byKey)) {
+		if networks := byKey[key]; len(networks) > 1 {
+			out = append(out, newAmbiguousNetworkError(key, networks))
+		}
+	}
+
+	return out
+}
+
+// AmbiguousKeys is a shortcut for [Registry.AmbiguousKeys] on the default registry.
+func AmbiguousKeys() []*AmbiguousNetworkError {
+	return defaultRegistry.AmbiguousKeys()
+}
+
+func newAmbiguousNetworkError(key string, networks []*registry.Network) *AmbiguousNetworkError {
+	err := &AmbiguousNetworkError{Key: key, Matches: make([]NetworkMatch, len(networks))}
+	for i, network := range networks {
+		err.Matches[i] = NetworkMatch{Network: network, Fields: matchFields(network, key)}
+	}
+
+	return err
+}
+
+func matchFields(network *registry.Network, key string) (fields []MatchField) {
+	if network.ID == key {
+		fields = append(fields, MatchID)
+	}
+	if slices.Contains(network.Aliases, key) {
+		fields = append(fields, MatchAlias)
+	}
+	if network.ShortName == key {
+		fields = append(fields, MatchShortName)
+	}
+	if network.FullName == key {
+		fields = append(fields, MatchFullName)
+	}
+	if network.Caip2ID == key {
+		fields = append(fields, MatchCAIP2)
+	}
+
+	return fields
+}
@@ -0,0 +1,64 @@
+package networks
+
+import (
+	"testing"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Resolve(t *testing.T) {
+	r := New(WithSources(staticSource(
+		registry.Network{ID: "mainnet", FullName: "Ethereum Mainnet", ShortName: "Ethereum", Aliases: []string{"eth"}},
+		registry.Network{ID: "sepolia", FullName: "Ethereum Sepolia Testnet", ShortName: "Ethereum", Aliases: []string{"sepolia"}},
+		registry.Network{ID: "eth", FullName: "Eth Chain", ShortName: "Eth"},
+	)))
+
+	network, err := r.Resolve("mainnet")
+	require.NoError(t, err)
+	assert.Equal(t, "mainnet", network.ID)
+
+	network, err = r.Resolve("Ethereum Sepolia Testnet")
+	require.NoError(t, err)
+	assert.Equal(t, "sepolia", network.ID)
+
+	network, err = r.Resolve("sepolia")
+	require.NoError(t, err, "an ID also being an alias of the same network isn't ambiguous")
+	assert.Equal(t, "sepolia", network.ID)
+
+	_, err = r.Resolve("Ethereum")
+	assert.ErrorIs(t, err, ErrAmbiguousNetwork)
+	assert.EqualError(t, err, `ambiguous network "Ethereum" matches "mainnet" (shortName), "sepolia" (shortName)`)
+
+	_, err = r.Resolve("eth")
+	var ambiguous *AmbiguousNetworkError
+	require.ErrorAs(t, err, &ambiguous)
+	assert.Equal(t, "eth", ambiguous.Key)
+	assert.Equal(t, []NetworkMatch{
+		{Network: r.Networks()["eth"], Fields: []MatchField{MatchID}},
+		{Network: r.Networks()["mainnet"], Fields: []MatchField{MatchAlias}},
+	}, ambiguous.Matches)
+	assert.Equal(t, "eth", r.Find("eth").ID, "Find keeps the ID precedence")
+
+	_, err = r.Resolve("ethereum")
+	assert.ErrorIs(t, err, ErrUnknownNetwork, "keys aren't normalized")
+}
+
+func TestRegistry_AmbiguousKeys(t *testing.T) {
+	r := New(WithSources(staticSource(
+		registry.Network{ID: "base", FullName: "Base Mainnet", ShortName: "Base"},
+		registry.Network{ID: "base-sepolia", FullName: "Base Sepolia Testnet", ShortName: "Base", Aliases: []string{"base"}},
+		registry.Network{ID: "gnosis", FullName: "Gnosis Mainnet", ShortName: "Gnosis"},
+	)))
+
+	keys := r.AmbiguousKeys()
+	require.Len(t, keys, 2)
+
+	assert.Equal(t, "Base", keys[0].Key)
+	assert.Equal(t, "base", keys[1].Key)
+	assert.Equal(t, []MatchField{MatchID}, keys[1].Matches[0].Fields)
+	assert.Equal(t, []MatchField{MatchAlias}, keys[1].Matches[1].Fields)
+
+	assert.NotEmpty(t, loadEmbeddedRegistry(t).AmbiguousKeys(), "short names are shared by mainnets and testnets")
+}
@@ -0,0 +1,63 @@
+package networks
+
+import (
+	"time"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+)
+
+// snapshot is a single generation of a loaded registry, the full registry alongside the views
+// derived from it. A snapshot is never modified once created, a refresh builds a new one and
+// publishes it atomically so readers always see views that are consistent with each other.
+type snapshot struct {
+	version   string
+	updatedAt time.Time
+	loadedAt  time.Time
+
+	// source is the name of the [Source] the registry was loaded from and sourceIndex its
+	// position in the chain of sources.
+	source      string
+	sourceIndex int
+
+	full       NetworkRegistry
+	firehose   NetworkRegistry
+	substreams NetworkRegistry
+
+	// index is the lookup index of full.
+	index *networkIndex
+
+	patchResults    []PatchResult
+	overrideReports []OverrideReport
+
+	// provenance tracks the networks touched by overrides, the others come from the registry.
+	provenance map[string]*networkProvenance
+
+	// native is the registry the snapshot is built from, never modified, and generation the
+	// registrations generation applied to it.
+	native     *registry.NetworksRegistry
+	generation uint64
+}
+
+func newSnapshot(native *registry.NetworksRegistry, full NetworkRegistry) *snapshot {
+	return &snapshot{
+		native:     native,
+		version:    native.Version,
+		updatedAt:  native.UpdatedAt,
+		loadedAt:   time.Now(),
+		full:       full,
+		firehose:   full.Filter(isFirehoseNetwork),
+		substreams: full.Filter(isSubstreamsNetwork),
+		index:      newNetworkIndex(full),
+	}
+}
+
+// registryProvenance is the provenance of the networks coming from the registry document.
+func (s *snapshot) registryProvenance() Provenance {
+	return Provenance{Kind: ProvenanceRegistry, Source: s.source, Version: s.version}
+}
+
+// loaded returns true if the snapshot comes from one of the sources of r, false when it's
+// the placeholder used while all of them fail.
+func (s *snapshot) loaded(r *Registry) bool {
+	return s.sourceIndex < len(r.sources)
+}
@@ -0,0 +1,334 @@
+package networks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+)
+
+// Source loads a registry document. A [Registry] holds an ordered chain of sources (see
+// [WithSources]), the first one to load successfully is used.
+type Source interface {
+	// Name identifies the source in logs and errors, like the URL or file path it loads from.
+	Name() string
+
+	// Load loads and parses the registry document.
+	Load(ctx context.Context) (*registry.NetworksRegistry, error)
+}
+
+// LatestSource loads the latest registry version compatible with this library from The Graph,
+// using the GitHub mirror when the main host fails. It's the first source of the default chain.
+func LatestSource() Source {
+	return &urlSource{
+		name:   "latest",
+		urls:   []string{registry.GetLatestVersionUrl(), registry.GetLatestVersionFallbackUrl()},
+		latest: true,
+	}
+}
+
+// URLSource loads the registry document served at url, for example an internal mirror of The
+// Graph's registry.
+func URLSource(url string) Source {
+	return &urlSource{name: url, urls: []string{url}}
+}
+
+// FileSource loads the registry document stored at path.
+func FileSource(path string) Source {
+	return &fileSource{path: path}
+}
+
+// EnvFileSource loads the registry document stored at the path held by the environment variable
+// envVar, read on each load. Loading fails when the variable is unset or empty.
+func EnvFileSource(envVar string) Source {
+	return &envFileSource{envVar: envVar}
+}
+
+// ReaderSource loads the registry document from reader. The reader is consumed on first load,
+// its content is kept so later loads, like refreshes, return the same document.
+func ReaderSource(name string, reader io.Reader) Source {
+	return &readerSource{name: name, reader: reader}
+}
+
+// EmbeddedSource loads the registry document embedded in this package, it never fails. It's the
+// last source of the default chain.
+func EmbeddedSource() Source {
+	return embeddedSource{}
+}
+
+// SourceFunc adapts a plain function to a [Source] identified by name.
+func SourceFunc(name string, load func(ctx context.Context) (*registry.NetworksRegistry, error)) Source {
+	return &funcSource{name: name, load: load}
+}
+
+// fromJSON parses a registry document, [registry.FromJSON] returns a non-nil registry even
+// when parsing fails which is easy to misuse.
+func fromJSON(content []byte) (*registry.NetworksRegistry, error) {
+	native, err := registry.FromJSON(content)
+	if err != nil {
+		return nil, fmt.Errorf("parse registry document: %w", err)
+	}
+
+	return native, nil
+}
+
+// ErrNotModified is returned by [Source.Load] when the registry document didn't change since it
+// was last loaded by the [Registry], see [ActiveVersion]. The registry in use is then kept as is
+// and the load isn't counted as a failure.
+var ErrNotModified = errors.New("registry document not modified")
+
+type activeVersionKey struct{}
+
+// ActiveVersion returns the version of the registry in use when ctx is given to [Source.Load] by
+// a [Registry] reloading the source that registry comes from, like on scheduled refreshes. A
+// source finding the same version can return [ErrNotModified] to skip rebuilding the registry.
+func ActiveVersion(ctx context.Context) (version string, ok bool) {
+	version, ok = ctx.Value(activeVersionKey{}).(string)
+	return
+}
+
+func withActiveVersion(ctx context.Context, version string) context.Context {
+	return context.WithValue(ctx, activeVersionKey{}, version)
+}
+
+type urlSource struct {
+	name   string
+	urls   []string
+	latest bool
+
+	lock       sync.Mutex
+	validators map[string]*urlValidators
+}
+
+// urlValidators holds the last document fetched from a URL along with the validators used to
+// fetch it again conditionally.
+type urlValidators struct {
+	etag         string
+	lastModified string
+	version      string
+	content      []byte
+}
+
+func (s *urlSource) Name() string { return s.name }
+
+func (s *urlSource) Load(ctx context.Context) (*registry.NetworksRegistry, error) {
+	var errs []error
+	for _, url := range s.urls {
+		native, err := s.fetch(ctx, url)
+		if err == nil || errors.Is(err, ErrNotModified) {
+			return native, err
+		}
+
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// fetch loads the document served at url, conditionally when it was fetched before so the host
+// answers with 304 Not Modified instead of the whole document when it didn't change.
+func (s *urlSource) fetch(ctx context.Context, url string) (*registry.NetworksRegistry, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request for %s: %w", url, err)
+	}
+
+	s.lock.Lock()
+	previous := s.validators[url]
+	s.lock.Unlock()
+
+	if previous != nil {
+		if previous.etag != "" {
+			request.Header.Set("If-None-Match", previous.etag)
+		}
+		if previous.lastModified != "" {
+			request.Header.Set("If-Modified-Since", previous.lastModified)
+		}
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", url, err)
+	}
+	defer response.Body.Close()
+
+	current := previous
+	switch {
+	case response.StatusCode == http.StatusNotModified && previous != nil:
+		// The document we have is still the one served
+
+	case response.StatusCode == http.StatusOK:
+		content, err := io.ReadAll(response.Body)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", url, err)
+		}
+
+		// Only the version is decoded here, the whole document is parsed once we know it's
+		// not the one in use already.
+		var header struct {
+			Version string `json:"version"`
+		}
+		if err := json.Unmarshal(content, &header); err != nil {
+			return nil, fmt.Errorf("parse registry document: %w", err)
+		}
+
+		current = &urlValidators{
+			etag:         response.Header.Get("ETag"),
+			lastModified: response.Header.Get("Last-Modified"),
+			version:      header.Version,
+			content:      content,
+		}
+
+		s.lock.Lock()
+		if current.etag == "" && current.lastModified == "" {
+			delete(s.validators, url)
+		} else {
+			if s.validators == nil {
+				s.validators = make(map[string]*urlValidators)
+			}
+			s.validators[url] = current
+		}
+		s.lock.Unlock()
+
+	default:
+		return nil, fmt.Errorf("fetch %s: HTTP %d", url, response.StatusCode)
+	}
+
+	if version, ok := ActiveVersion(ctx); ok && version == current.version {
+		return nil, ErrNotModified
+	}
+
+	if err := VerifyDocument(ctx, url, current.content); err != nil {
+		return nil, err
+	}
+
+	return fromJSON(current.content)
+}
+
+type fileSource struct {
+	path string
+}
+
+func (s *fileSource) Name() string { return s.path }
+
+func (s *fileSource) Load(ctx context.Context) (*registry.NetworksRegistry, error) {
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := VerifyDocument(ctx, s.path, content); err != nil {
+		return nil, err
+	}
+
+	return fromJSON(content)
+}
+
+type envFileSource struct {
+	envVar string
+}
+
+func (s *envFileSource) Name() string { return "$" + s.envVar }
+
+func (s *envFileSource) Load(ctx context.Context) (*registry.NetworksRegistry, error) {
+	path := os.Getenv(s.envVar)
+	if path == "" {
+		return nil, fmt.Errorf("environment variable %s is not set", s.envVar)
+	}
+
+	return (&fileSource{path: path}).Load(ctx)
+}
+
+type readerSource struct {
+	name string
+
+	once    sync.Once
+	reader  io.Reader
+	content []byte
+	err     error
+}
+
+func (s *readerSource) Name() string { return s.name }
+
+func (s *readerSource) Load(ctx context.Context) (*registry.NetworksRegistry, error) {
+	s.once.Do(func() {
+		s.content, s.err = io.ReadAll(s.reader)
+		s.reader = nil
+	})
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	if err := VerifyDocument(ctx, s.name, s.content); err != nil {
+		return nil, err
+	}
+
+	return fromJSON(s.content)
+}
+
+type embeddedSource struct{}
+
+func (embeddedSource) Name() string { return "embedded" }
+
+func (embeddedSource) Load(_ context.Context) (*registry.NetworksRegistry, error) {
+	return fromEmbeddedJSON()
+}
+
+type cacheSource struct {
+	dir string
+}
+
+func (s *cacheSource) Name() string { return "cache" }
+
+func (s *cacheSource) Load(ctx context.Context) (*registry.NetworksRegistry, error) {
+	native, _, err := s.loadFetchedAt(ctx)
+	return native, err
+}
+
+// loadFetchedAt is like Load, also returning when the cached registry was fetched from its
+// original source. The cached document is verified again with the location it was loaded from.
+func (s *cacheSource) loadFetchedAt(ctx context.Context) (*registry.NetworksRegistry, time.Time, error) {
+	cache, err := readRegistryCache(s.dir)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	location := cache.Location
+	if location == "" {
+		location = filepath.Join(s.dir, cacheFileName)
+	}
+
+	if err := VerifyDocument(ctx, location, []byte(cache.Document)); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	native, err := fromJSON([]byte(cache.Document))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return native, cache.FetchedAt, nil
+}
+
+type funcSource struct {
+	name string
+	load func(ctx context.Context) (*registry.NetworksRegistry, error)
+}
+
+func (s *funcSource) Name() string { return s.name }
+
+func (s *funcSource) Load(ctx context.Context) (*registry.NetworksRegistry, error) {
+	return s.load(ctx)
+}
@@ -0,0 +1,303 @@
+package networks
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRegistryDocument = `{
+	"$schema": "https://networks-registry.thegraph.com/TheGraphNetworksRegistrySchema_v0_7.json",
+	"version": "0.7.99",
+	"updatedAt": "2025-10-01T00:00:00Z",
+	"networks": [{"id": "mirrored", "fullName": "Mirrored Chain", "shortName": "Mirrored", "caip2Id": "mirror:1", "networkType": "mainnet", "services": {}, "issuanceRewards": false}]
+}`
+
+func TestSources(t *testing.T) {
+	ctx := context.Background()
+
+	assertLoaded := func(t *testing.T, source Source) {
+		t.Helper()
+
+		native, err := source.Load(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "0.7.99", native.Version)
+		require.Len(t, native.Networks, 1)
+		assert.Equal(t, "mirrored", native.Networks[0].ID)
+	}
+
+	path := filepath.Join(t.TempDir(), "registry.json")
+	require.NoError(t, os.WriteFile(path, []byte(testRegistryDocument), 0o644))
+
+	t.Run("url", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/registry.json" {
+				http.NotFound(w, r)
+				return
+			}
+
+			w.Write([]byte(testRegistryDocument))
+		}))
+		defer server.Close()
+
+		source := URLSource(server.URL + "/registry.json")
+		assert.Equal(t, server.URL+"/registry.json", source.Name())
+		assertLoaded(t, source)
+
+		_, err := URLSource(server.URL + "/missing.json").Load(ctx)
+		assert.ErrorContains(t, err, "HTTP 404")
+	})
+
+	t.Run("url tries each url in order", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/mirror.json" {
+				w.Write([]byte(testRegistryDocument))
+				return
+			}
+
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		assertLoaded(t, &urlSource{name: "test", urls: []string{server.URL + "/main.json", server.URL + "/mirror.json"}})
+	})
+
+	t.Run("file", func(t *testing.T) {
+		assertLoaded(t, FileSource(path))
+
+		_, err := FileSource(filepath.Join(t.TempDir(), "missing.json")).Load(ctx)
+		assert.Error(t, err)
+	})
+
+	t.Run("env file", func(t *testing.T) {
+		source := EnvFileSource("TEST_FIREHOSE_NETWORKS_REGISTRY")
+		assert.Equal(t, "$TEST_FIREHOSE_NETWORKS_REGISTRY", source.Name())
+
+		t.Setenv("TEST_FIREHOSE_NETWORKS_REGISTRY", "")
+		_, err := source.Load(ctx)
+		assert.ErrorContains(t, err, "TEST_FIREHOSE_NETWORKS_REGISTRY is not set")
+
+		t.Setenv("TEST_FIREHOSE_NETWORKS_REGISTRY", path)
+		assertLoaded(t, source)
+	})
+
+	t.Run("reader can be loaded many times", func(t *testing.T) {
+		source := ReaderSource("reader", strings.NewReader(testRegistryDocument))
+
+		assertLoaded(t, source)
+		assertLoaded(t, source)
+	})
+
+	t.Run("invalid document", func(t *testing.T) {
+		_, err := ReaderSource("reader", strings.NewReader("{")).Load(ctx)
+		assert.ErrorContains(t, err, "parse registry document")
+	})
+
+	t.Run("embedded", func(t *testing.T) {
+		native, err := EmbeddedSource().Load(ctx)
+		require.NoError(t, err)
+		assert.NotEmpty(t, native.Networks)
+	})
+}
+
+func TestRegistry_SourceChain(t *testing.T) {
+	t.Run("first successful source wins", func(t *testing.T) {
+		r := New(WithSources(failingSource, ReaderSource("mirror", strings.NewReader(testRegistryDocument)), EmbeddedSource()))
+
+		assert.True(t, r.Has("mirrored"))
+		assert.False(t, r.Has("mainnet"))
+		assert.Equal(t, "mirror", r.snapshot().source)
+	})
+
+	t.Run("preferred source replaces the fallback once available", func(t *testing.T) {
+		var available atomic.Bool
+		preferred := SourceFunc("preferred", func(ctx context.Context) (*registry.NetworksRegistry, error) {
+			if !available.Load() {
+				return failingSource.Load(ctx)
+			}
+
+			return staticSource(registry.Network{ID: "preferred"}).Load(ctx)
+		})
+
+		r := New(WithSources(preferred, staticSource(registry.Network{ID: "fallback"})))
+		require.True(t, r.Has("fallback"))
+
+		available.Store(true)
+		assert.Eventually(t, func() bool { return r.Has("preferred") }, 5*time.Second, time.Millisecond)
+	})
+
+	t.Run("refresh never downgrades to a less preferred source", func(t *testing.T) {
+		var available atomic.Bool
+		available.Store(true)
+		preferred := SourceFunc("preferred", func(ctx context.Context) (*registry.NetworksRegistry, error) {
+			if !available.Load() {
+				return failingSource.Load(ctx)
+			}
+
+			return staticSource(registry.Network{ID: "preferred"}).Load(ctx)
+		})
+
+		r := New(WithSources(preferred, staticSource(registry.Network{ID: "fallback"})))
+		require.True(t, r.Has("preferred"))
+
+		available.Store(false)
+
+		_, err := r.loadLatest(context.Background())
+		assert.Error(t, err, "only the preferred source is tried")
+		assert.True(t, r.Has("preferred"))
+
+		available.Store(true)
+		refreshed, err := r.loadLatest(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "preferred", refreshed.source)
+	})
+
+	t.Run("fallback loaded by a refresh doesn't replace the preferred source installed meanwhile", func(t *testing.T) {
+		var available atomic.Bool
+		preferred := SourceFunc("preferred", func(ctx context.Context) (*registry.NetworksRegistry, error) {
+			if !available.Load() {
+				return failingSource.Load(ctx)
+			}
+
+			return staticSource(registry.Network{ID: "preferred"}).Load(ctx)
+		})
+
+		r := New(WithSources(preferred, staticSource(registry.Network{ID: "fallback"})))
+		require.True(t, r.Has("fallback"))
+
+		// A scheduled refresh loads the fallback while the preferred source still fails...
+		refreshed, err := r.loadLatest(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "static", refreshed.source)
+
+		// ...and the background retry installs the preferred source before the refresh does.
+		available.Store(true)
+		retried, err := r.loadChain(context.Background(), 1)
+		require.NoError(t, err)
+		r.refresh(retried)
+		require.True(t, r.Has("preferred"))
+
+		r.refresh(refreshed)
+		assert.True(t, r.Has("preferred"))
+		assert.False(t, r.Has("fallback"))
+		assert.Equal(t, "preferred", r.Status().Source)
+	})
+}
+
+func TestURLSource_ConditionalFetch(t *testing.T) {
+	ctx := context.Background()
+
+	newServer := func(t *testing.T, document *atomic.Value, withValidators bool) (*httptest.Server, *atomic.Int64, *atomic.Int64) {
+		var requests, notModified atomic.Int64
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+
+			content := document.Load().(string)
+			etag := fmt.Sprintf(`"%x"`, sha256.Sum256([]byte(content)))
+			if withValidators {
+				w.Header().Set("ETag", etag)
+				if r.Header.Get("If-None-Match") == etag {
+					notModified.Add(1)
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+			}
+
+			w.Write([]byte(content))
+		}))
+		t.Cleanup(server.Close)
+
+		return server, &requests, &notModified
+	}
+
+	t.Run("not modified document is returned again", func(t *testing.T) {
+		var document atomic.Value
+		document.Store(testRegistryDocument)
+		server, requests, notModified := newServer(t, &document, true)
+
+		source := URLSource(server.URL)
+		for range 3 {
+			native, err := source.Load(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, "0.7.99", native.Version)
+		}
+
+		assert.Equal(t, int64(3), requests.Load())
+		assert.Equal(t, int64(2), notModified.Load())
+	})
+
+	t.Run("last modified", func(t *testing.T) {
+		lastModified := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+
+		var notModified atomic.Int64
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.ServeContent(w, r, "registry.json", lastModified, strings.NewReader(testRegistryDocument))
+			if r.Header.Get("If-Modified-Since") != "" {
+				notModified.Add(1)
+			}
+		}))
+		defer server.Close()
+
+		source := URLSource(server.URL)
+		for range 2 {
+			_, err := source.Load(ctx)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, int64(1), notModified.Load())
+	})
+
+	t.Run("active version is not modified", func(t *testing.T) {
+		var document atomic.Value
+		document.Store(testRegistryDocument)
+		server, _, _ := newServer(t, &document, false)
+
+		_, err := URLSource(server.URL).Load(withActiveVersion(ctx, "0.7.99"))
+		assert.ErrorIs(t, err, ErrNotModified)
+
+		native, err := URLSource(server.URL).Load(withActiveVersion(ctx, "0.7.98"))
+		require.NoError(t, err)
+		assert.Equal(t, "0.7.99", native.Version)
+	})
+
+	t.Run("scheduled refresh keeps the snapshot until the version changes", func(t *testing.T) {
+		for _, withValidators := range []bool{true, false} {
+			var document atomic.Value
+			document.Store(testRegistryDocument)
+			server, requests, _ := newServer(t, &document, withValidators)
+
+			r := New(WithSources(URLSource(server.URL)))
+			require.NoError(t, r.Load(ctx))
+			initial := r.snapshot()
+
+			var updates atomic.Int64
+			r.Subscribe(func(Update) { updates.Add(1) })
+
+			refreshCtx, cancel := context.WithCancel(ctx)
+			r.ScheduleUpdateLatestRegistry(refreshCtx, time.Millisecond)
+
+			assert.Eventually(t, func() bool { return requests.Load() > 3 }, 5*time.Second, time.Millisecond)
+			assert.Same(t, initial, r.snapshot())
+			assert.Zero(t, updates.Load())
+			assert.False(t, r.Status().LastRefreshAt.Before(initial.loadedAt))
+
+			document.Store(strings.Replace(testRegistryDocument, "0.7.99", "0.7.100", 1))
+			assert.Eventually(t, func() bool { return updates.Load() == 1 }, 5*time.Second, time.Millisecond)
+			assert.Equal(t, "0.7.100", r.Status().Version)
+
+			cancel()
+		}
+	})
+}
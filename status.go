@@ -0,0 +1,192 @@
+package networks
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SourceKind classifies where the active registry of a [Registry] comes from.
+type SourceKind string
+
+const (
+	// SourceKindNone is reported while no source could be loaded yet.
+	SourceKindNone SourceKind = "none"
+	// SourceKindRemote is a registry fetched over HTTP, see [LatestSource] and [URLSource].
+	SourceKindRemote SourceKind = "remote"
+	// SourceKindCache is a registry read back from the on-disk cache, see [WithCacheDir].
+	SourceKindCache SourceKind = "cache"
+	// SourceKindEmbedded is the registry embedded in this package, see [EmbeddedSource].
+	SourceKindEmbedded SourceKind = "embedded"
+	// SourceKindCustom is a registry coming from any other source, like [FileSource] or [SourceFunc].
+	SourceKindCustom SourceKind = "custom"
+)
+
+// kindedSource is implemented by built-in sources whose kind is not [SourceKindCustom].
+type kindedSource interface {
+	kind() SourceKind
+}
+
+func (s *urlSource) kind() SourceKind   { return SourceKindRemote }
+func (s *cacheSource) kind() SourceKind { return SourceKindCache }
+func (embeddedSource) kind() SourceKind { return SourceKindEmbedded }
+
+func sourceKind(source Source) SourceKind {
+	if kinded, ok := source.(kindedSource); ok {
+		return kinded.kind()
+	}
+
+	return SourceKindCustom
+}
+
+// RegistryStatus describes the provenance and health of the registry in use by a [Registry],
+// see [Registry.Status].
+type RegistryStatus struct {
+	// Loaded is false until a source could be loaded, either because no lookup happened yet
+	// or because all sources failed so far.
+	Loaded bool `json:"loaded"`
+
+	// Source is the [Source.Name] of the source the active registry comes from.
+	Source string `json:"source,omitempty"`
+	// SourceKind classifies [RegistryStatus.Source].
+	SourceKind SourceKind `json:"sourceKind"`
+
+	// Version is the `version` of the active registry document.
+	Version string `json:"version,omitempty"`
+	// UpdatedAt is the `updatedAt` of the active registry document, when it was generated upstream.
+	UpdatedAt time.Time `json:"updatedAt,omitzero"`
+	// LoadedAt is when the active registry was fetched from its source, for the cache when it was
+	// fetched from the source it was cached from.
+	LoadedAt time.Time `json:"loadedAt,omitzero"`
+
+	// LastRefreshAt is the last time a source was successfully loaded, initial load included.
+	LastRefreshAt time.Time `json:"lastRefreshAt,omitzero"`
+	// LastFailedRefreshAt is the last time no source could be loaded, initial load included.
+	LastFailedRefreshAt time.Time `json:"lastFailedRefreshAt,omitzero"`
+	// LastError is the error of the last failed refresh, kept after later successful ones, or
+	// the invalid options failing every load, see [Registry.Load].
+	LastError error `json:"-"`
+
+	// Retrying is true while the sources preceding the one in use are retried in the background.
+	Retrying bool `json:"retrying"`
+
+	// OverridesError is why overrides files were skipped, see [WithOverridesFile].
+	OverridesError error `json:"-"`
+}
+
+// MarshalJSON renders the status with [RegistryStatus.LastError] and
+// [RegistryStatus.OverridesError] as their messages.
+func (s RegistryStatus) MarshalJSON() ([]byte, error) {
+	type plain RegistryStatus
+
+	out := struct {
+		plain
+		LastError      string `json:"lastError,omitempty"`
+		OverridesError string `json:"overridesError,omitempty"`
+	}{plain: plain(s)}
+
+	if s.LastError != nil {
+		out.LastError = s.LastError.Error()
+	}
+	if s.OverridesError != nil {
+		out.OverridesError = s.OverridesError.Error()
+	}
+
+	return json.Marshal(out)
+}
+
+// MarshalLogObject renders the status as a zap object, for example for startup logs with
+// `zap.Object("registry", networks.Status())`.
+func (s RegistryStatus) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
+	encoder.AddBool("loaded", s.Loaded)
+	encoder.AddString("source", s.Source)
+	encoder.AddString("source_kind", string(s.SourceKind))
+	encoder.AddString("version", s.Version)
+	if !s.UpdatedAt.IsZero() {
+		encoder.AddTime("updated_at", s.UpdatedAt)
+	}
+	if !s.LoadedAt.IsZero() {
+		encoder.AddTime("loaded_at", s.LoadedAt)
+	}
+	if !s.LastRefreshAt.IsZero() {
+		encoder.AddTime("last_refresh_at", s.LastRefreshAt)
+	}
+	if !s.LastFailedRefreshAt.IsZero() {
+		encoder.AddTime("last_failed_refresh_at", s.LastFailedRefreshAt)
+	}
+	if s.LastError != nil {
+		encoder.AddString("last_error", s.LastError.Error())
+	}
+	encoder.AddBool("retrying", s.Retrying)
+	if s.OverridesError != nil {
+		encoder.AddString("overrides_error", s.OverridesError.Error())
+	}
+
+	return nil
+}
+
+// refreshStatus tracks the outcome of the loads performed by a [Registry].
+type refreshStatus struct {
+	lock        sync.Mutex
+	lastRefresh time.Time
+	lastFailure time.Time
+	lastErr     error
+
+	overridesErr error
+}
+
+func (s *refreshStatus) recordOverrides(err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.overridesErr = err
+}
+
+func (s *refreshStatus) record(err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err != nil {
+		s.lastFailure = time.Now()
+		s.lastErr = err
+		return
+	}
+
+	s.lastRefresh = time.Now()
+}
+
+// Status returns the provenance and health of the registry in use. It doesn't trigger
+// loading, [RegistryStatus.Loaded] is false until the registry is loaded.
+func (r *Registry) Status() RegistryStatus {
+	status := RegistryStatus{SourceKind: SourceKindNone, Retrying: r.retrying.Load()}
+
+	r.refreshStatus.lock.Lock()
+	status.LastRefreshAt = r.refreshStatus.lastRefresh
+	status.LastFailedRefreshAt = r.refreshStatus.lastFailure
+	status.LastError = r.refreshStatus.lastErr
+	status.OverridesError = r.refreshStatus.overridesErr
+	r.refreshStatus.lock.Unlock()
+
+	if r.configErr != nil {
+		status.LastError = fmt.Errorf("invalid registry configuration: %w", r.configErr)
+	}
+
+	if snap := r.current.Load(); snap != nil && snap.loaded(r) {
+		status.Loaded = true
+		status.Source = snap.source
+		status.SourceKind = sourceKind(r.sources[snap.sourceIndex])
+		status.Version = snap.version
+		status.UpdatedAt = snap.updatedAt
+		status.LoadedAt = snap.loadedAt
+	}
+
+	return status
+}
+
+// Status is a shortcut for [Registry.Status] on the default registry.
+func Status() RegistryStatus {
+	return defaultRegistry.Status()
+}
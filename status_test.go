@@ -0,0 +1,94 @@
+package networks
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRegistry_Status(t *testing.T) {
+	t.Run("not loaded", func(t *testing.T) {
+		status := New(WithSources(EmbeddedSource())).Status()
+
+		assert.False(t, status.Loaded)
+		assert.Equal(t, SourceKindNone, status.SourceKind)
+		assert.Empty(t, status.Version)
+	})
+
+	t.Run("loaded from the embedded registry", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		r := New(WithSources(slowSource, EmbeddedSource()))
+		require.NoError(t, r.Load(ctx))
+
+		status := r.Status()
+		assert.True(t, status.Loaded)
+		assert.Equal(t, "embedded", status.Source)
+		assert.Equal(t, SourceKindEmbedded, status.SourceKind)
+		assert.Equal(t, "0.7.34", status.Version)
+		assert.Equal(t, time.Date(2025, 9, 30, 14, 2, 50, 406000000, time.UTC), status.UpdatedAt)
+		assert.False(t, status.LoadedAt.IsZero())
+		assert.False(t, status.LastRefreshAt.IsZero())
+		assert.True(t, status.LastFailedRefreshAt.IsZero(), "a fallback source is not a failed refresh")
+		assert.True(t, status.Retrying, "the slow source must be retried in the background")
+	})
+
+	t.Run("failures are reported", func(t *testing.T) {
+		r := New(WithSources(failingSource))
+		require.Error(t, r.Load(context.Background()))
+
+		status := r.Status()
+		assert.False(t, status.Loaded)
+		assert.False(t, status.LastFailedRefreshAt.IsZero())
+		assert.ErrorContains(t, status.LastError, "registry unavailable")
+	})
+
+	t.Run("source kinds", func(t *testing.T) {
+		assert.Equal(t, SourceKindRemote, sourceKind(LatestSource()))
+		assert.Equal(t, SourceKindRemote, sourceKind(URLSource("https://mirror.internal/registry.json")))
+		assert.Equal(t, SourceKindCache, sourceKind(&cacheSource{}))
+		assert.Equal(t, SourceKindEmbedded, sourceKind(EmbeddedSource()))
+		assert.Equal(t, SourceKindCustom, sourceKind(FileSource("registry.json")))
+		assert.Equal(t, SourceKindCustom, sourceKind(failingSource))
+	})
+}
+
+func TestRegistryStatus_Renderers(t *testing.T) {
+	r := New(WithSources(failingSource, staticSource()))
+	require.NoError(t, r.Load(context.Background()))
+
+	status := r.Status()
+	status.LastError = &LoadError{Failures: []*SourceError{{Source: "failing", Err: assert.AnError}}}
+
+	t.Run("json", func(t *testing.T) {
+		content, err := json.Marshal(status)
+		require.NoError(t, err)
+
+		var out map[string]any
+		require.NoError(t, json.Unmarshal(content, &out))
+		assert.Equal(t, true, out["loaded"])
+		assert.Equal(t, "static", out["source"])
+		assert.Equal(t, "custom", out["sourceKind"])
+		assert.Equal(t, "0.0.1", out["version"])
+		assert.True(t, strings.HasPrefix(out["lastError"].(string), "load registry: all sources failed"))
+		assert.NotContains(t, out, "updatedAt", "zero times are omitted")
+	})
+
+	t.Run("log", func(t *testing.T) {
+		core, logs := observer.New(zap.InfoLevel)
+		zap.New(core).Info("registry", zap.Object("registry", status))
+
+		fields := logs.All()[0].ContextMap()["registry"].(map[string]any)
+		assert.Equal(t, "static", fields["source"])
+		assert.Equal(t, "custom", fields["source_kind"])
+		assert.Contains(t, fields, "last_error")
+	})
+}
@@ -0,0 +1,120 @@
+package networks
+
+import (
+	"sync"
+)
+
+// Update describes a refresh that replaced the active registry of a [Registry].
+type Update struct {
+	// OldVersion is the registry version that was active before the refresh.
+	OldVersion string
+
+	// NewVersion is the registry version active after the refresh.
+	NewVersion string
+
+	// Diff lists the networks added, removed or modified by the refresh.
+	Diff *RegistryDiff
+}
+
+type subscribers struct {
+	lock   sync.Mutex
+	nextID uint64
+	fns    map[uint64]func(Update)
+
+	// pending are the installations not sent yet, in the order they happened, and delivering is
+	// true while a goroutine is sending them.
+	pending    []installation
+	delivering bool
+}
+
+// installation is a snapshot installed in place of another one.
+type installation struct {
+	old, new *snapshot
+}
+
+// Subscribe registers fn to be called each time a background refresh, either the retry started
+// when the initial load falls back or one scheduled through [Registry.ScheduleUpdateLatestRegistry],
+// installs a registry that differs from the active one. Registrations through
+// [Registry.RegisterNetwork] and [Registry.RegisterServiceOverride] trigger it too, the initial
+// load doesn't.
+//
+// Updates are sent one at a time in the order the registries were installed, so fn is never
+// called concurrently. It's called from the refreshing goroutine once the new registry is active,
+// or from the one sending earlier updates, and may use the registry, including [Registry.Load] and
+// registrations. It should return quickly and hand off any long work like reconnecting to another
+// goroutine. Call the returned function to stop receiving updates.
+func (r *Registry) Subscribe(fn func(Update)) (unsubscribe func()) {
+	r.subscribers.lock.Lock()
+	defer r.subscribers.lock.Unlock()
+
+	if r.subscribers.fns == nil {
+		r.subscribers.fns = make(map[uint64]func(Update))
+	}
+
+	id := r.subscribers.nextID
+	r.subscribers.nextID++
+	r.subscribers.fns[id] = fn
+
+	return func() {
+		r.subscribers.lock.Lock()
+		defer r.subscribers.lock.Unlock()
+
+		delete(r.subscribers.fns, id)
+	}
+}
+
+// Subscribe is a shortcut for [Registry.Subscribe] on the default registry.
+func Subscribe(fn func(Update)) (unsubscribe func()) {
+	return defaultRegistry.Subscribe(fn)
+}
+
+// enqueue records the installation of new in place of old to be sent by [Registry.notify]. It's
+// called while installing so that updates are queued in installation order.
+func (s *subscribers) enqueue(old, new *snapshot) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(s.fns) > 0 {
+		s.pending = append(s.pending, installation{old, new})
+	}
+}
+
+// notify sends the pending updates to every subscriber, one at a time in installation order.
+// When another goroutine is already sending them, including a subscriber of the current update
+// triggering a refresh, it's left to send the new ones and notify returns right away. Nothing is
+// sent for an installation whose snapshots are equivalent.
+//
+// It must not be called while holding loadLock, a subscriber calling [Registry.Load] would
+// deadlock.
+func (r *Registry) notify() {
+	r.subscribers.lock.Lock()
+	if r.subscribers.delivering {
+		r.subscribers.lock.Unlock()
+		return
+	}
+	r.subscribers.delivering = true
+
+	for len(r.subscribers.pending) > 0 {
+		next := r.subscribers.pending[0]
+		r.subscribers.pending = r.subscribers.pending[1:]
+
+		fns := make([]func(Update), 0, len(r.subscribers.fns))
+		for _, fn := range r.subscribers.fns {
+			fns = append(fns, fn)
+		}
+		r.subscribers.lock.Unlock()
+
+		diff := Diff(next.old.full, next.new.full)
+		if !diff.IsEmpty() || next.old.version != next.new.version {
+			update := Update{OldVersion: next.old.version, NewVersion: next.new.version, Diff: diff}
+			for _, fn := range fns {
+				fn(update)
+			}
+		}
+
+		r.subscribers.lock.Lock()
+	}
+
+	r.subscribers.delivering = false
+	r.subscribers.lock.Unlock()
+}
@@ -0,0 +1,147 @@
+package networks
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Subscribe(t *testing.T) {
+	var document atomic.Pointer[registry.NetworksRegistry]
+	source := SourceFunc("document", func(context.Context) (*registry.NetworksRegistry, error) {
+		// Each load must return fresh networks, like a real source parsing a document would
+		current := *document.Load()
+		current.Networks = slices.Clone(current.Networks)
+		return &current, nil
+	})
+
+	document.Store(&registry.NetworksRegistry{Version: "0.0.1", Networks: []registry.Network{
+		{ID: "alpha", Services: registry.Services{Firehose: []string{"alpha:443"}}},
+		{ID: "beta"},
+	}})
+
+	r := New(WithSources(source))
+	require.True(t, r.Has("alpha"))
+
+	updates := make(chan Update, 16)
+	unsubscribe := r.Subscribe(func(update Update) { updates <- update })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r.ScheduleUpdateLatestRegistry(ctx, time.Millisecond)
+
+	// Same document refreshed many times, nothing should be sent
+	select {
+	case update := <-updates:
+		t.Fatalf("unexpected update %+v", update)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	document.Store(&registry.NetworksRegistry{Version: "0.0.2", Networks: []registry.Network{
+		{ID: "alpha", Services: registry.Services{Firehose: []string{"alpha-new:443"}}},
+		{ID: "gamma"},
+	}})
+
+	var update Update
+	select {
+	case update = <-updates:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for update")
+	}
+
+	assert.Equal(t, "0.0.1", update.OldVersion)
+	assert.Equal(t, "0.0.2", update.NewVersion)
+	require.Len(t, update.Diff.Added, 1)
+	assert.Equal(t, "gamma", update.Diff.Added[0].ID)
+	require.Len(t, update.Diff.Removed, 1)
+	assert.Equal(t, "beta", update.Diff.Removed[0].ID)
+	require.Len(t, update.Diff.Modified, 1)
+	assert.Equal(t, "alpha", update.Diff.Modified[0].ID)
+	assert.Equal(t, []string{"alpha:443"}, update.Diff.Modified[0].Old.Services.Firehose)
+	assert.Equal(t, []string{"alpha-new:443"}, update.Diff.Modified[0].New.Services.Firehose)
+	assert.True(t, update.Diff.Affects("alpha"))
+	assert.False(t, update.Diff.Affects("delta"))
+
+	// Already the active registry when subscribers are called
+	assert.Equal(t, "alpha-new:443", r.FirehoseEndpoint("alpha"))
+
+	unsubscribe()
+	document.Store(&registry.NetworksRegistry{Version: "0.0.3"})
+
+	assert.Eventually(t, func() bool { return !r.Has("alpha") }, time.Second, time.Millisecond)
+	select {
+	case update := <-updates:
+		t.Fatalf("unexpected update after unsubscribe %+v", update)
+	default:
+	}
+}
+
+func TestRegistry_Subscribe_Ordering(t *testing.T) {
+	r := New(WithSources(versionedSource(registry.Network{ID: "alpha"})))
+	require.NoError(t, r.Load(context.Background()))
+
+	var inFlight atomic.Int64
+	var lock sync.Mutex
+	var updates []Update
+	r.Subscribe(func(update Update) {
+		assert.Equal(t, int64(1), inFlight.Add(1), "subscribers must not be called concurrently")
+		defer inFlight.Add(-1)
+
+		lock.Lock()
+		updates = append(updates, update)
+		lock.Unlock()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for range 4 {
+		r.ScheduleUpdateLatestRegistry(ctx, time.Millisecond)
+	}
+
+	assert.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(updates) >= 50
+	}, 5*time.Second, time.Millisecond)
+	cancel()
+
+	lock.Lock()
+	defer lock.Unlock()
+	for i := 1; i < len(updates); i++ {
+		require.Equal(t, updates[i-1].NewVersion, updates[i].OldVersion, "update %d must follow the previous one", i)
+	}
+}
+
+func TestRegistry_Subscribe_LoadFromSubscriber(t *testing.T) {
+	var available atomic.Bool
+	source := SourceFunc("toggled", func(ctx context.Context) (*registry.NetworksRegistry, error) {
+		if !available.Load() {
+			return failingSource.Load(ctx)
+		}
+
+		return staticSource(registry.Network{ID: "alpha"}).Load(ctx)
+	})
+
+	r := New(WithSources(source))
+	require.False(t, r.Has("alpha"), "all sources fail, custom networks only")
+
+	loaded := make(chan error, 1)
+	r.Subscribe(func(Update) { loaded <- r.Load(context.Background()) })
+
+	available.Store(true)
+	require.NoError(t, r.Load(context.Background()))
+
+	select {
+	case err := <-loaded:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscriber calling Load deadlocked")
+	}
+}
@@ -0,0 +1,182 @@
+package networks
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+)
+
+// suggestionsCount is the number of suggestions of the [UnknownNetworkError] returned by
+// [Registry.Lookup].
+const suggestionsCount = 3
+
+// UnknownNetworkError is returned when a key matches no network, with the networks the key was
+// likely meant for. It matches [ErrUnknownNetwork] with [errors.Is].
+type UnknownNetworkError struct {
+	Key string
+
+	// Suggestions are the closest networks to Key, best first, see [Registry.Suggest].
+	Suggestions []*registry.Network
+}
+
+func (e *UnknownNetworkError) Error() string {
+	if len(e.Suggestions) == 0 {
+		return fmt.Sprintf("unknown network %q", e.Key)
+	}
+
+	ids := make([]string, len(e.Suggestions))
+	for i, network := range e.Suggestions {
+		ids[i] = fmt.Sprintf("%q", network.ID)
+	}
+
+	suggestions := ids[0]
+	if len(ids) > 1 {
+		suggestions = strings.Join(ids[:len(ids)-1], ", ") + " or " + ids[len(ids)-1]
+	}
+
+	return fmt.Sprintf("unknown network %q, did you mean %s?", e.Key, suggestions)
+}
+
+func (e *UnknownNetworkError) Unwrap() error {
+	return ErrUnknownNetwork
+}
+
+// Lookup is like [Registry.FindNormalized] but returns an [*UnknownNetworkError] with
+// suggestions when no network matches key, ready to be shown to the user who typed it.
+func (r *Registry) Lookup(key string) (*registry.Network, error) {
+	if network := r.FindNormalized(key); network != nil {
+		return network, nil
+	}
+
+	return nil, r.unknownNetworkError(key)
+}
+
+// Lookup is a shortcut for [Registry.Lookup] on the default registry.
+func Lookup(key string) (*registry.Network, error) {
+	return defaultRegistry.Lookup(key)
+}
+
+func (r *Registry) unknownNetworkError(key string) *UnknownNetworkError {
+	return &UnknownNetworkError{Key: key, Suggestions: r.Suggest(key, suggestionsCount)}
+}
+
+// Suggest returns up to n networks whose ID, aliases, short name, full name or second name are
+// the closest to key, best first. Keys are compared once normalized by [NormalizeKey], by edit
+// distance, a name starting with key being as close as a single typo. Networks too far from key
+// to be what it was meant for are never suggested, so it can return fewer than n networks.
+func (r *Registry) Suggest(key string, n int) []*registry.Network {
+	normalized := NormalizeKey(key)
+	if n <= 0 || normalized == "" {
+		return nil
+	}
+
+	type suggestion struct {
+		network *registry.Network
+		// distance is how far the closest name is from key, and priority the rank of its field
+		// to break ties: IDs and aliases, then short names, full names and second names.
+		distance, priority int
+	}
+
+	networks := r.Networks()
+	var suggestions []suggestion
+	for _, id := range slices.Sorted(maps.Keys(networks)) {
+		network := networks[id]
+
+		best := suggestion{network: network, distance: -1}
+		for priority, names := range suggestionNames(network) {
+			for _, name := range names {
+				distance := suggestionDistance(normalized, NormalizeKey(name))
+				if distance >= 0 && (best.distance == -1 || distance < best.distance) {
+					best.distance, best.priority = distance, priority
+				}
+			}
+		}
+
+		if best.distance >= 0 {
+			suggestions = append(suggestions, best)
+		}
+	}
+
+	// Stable so that networks as close as each other stay sorted by ID.
+	slices.SortStableFunc(suggestions, func(a, b suggestion) int {
+		return cmp.Or(cmp.Compare(a.distance, b.distance), cmp.Compare(a.priority, b.priority))
+	})
+
+	out := make([]*registry.Network, 0, min(n, len(suggestions)))
+	for _, suggestion := range suggestions[:min(n, len(suggestions))] {
+		out = append(out, suggestion.network)
+	}
+
+	return out
+}
+
+// Suggest is a shortcut for [Registry.Suggest] on the default registry.
+func Suggest(key string, n int) []*registry.Network {
+	return defaultRegistry.Suggest(key, n)
+}
+
+// suggestionNames returns the names of network by decreasing priority.
+func suggestionNames(network *registry.Network) [][]string {
+	names := [][]string{append([]string{network.ID}, network.Aliases...), {network.ShortName}, {network.FullName}}
+	if network.SecondName != nil {
+		names = append(names, []string{*network.SecondName})
+	}
+
+	return names
+}
+
+// suggestionDistance returns how far name is from key, both normalized, or -1 when name is too
+// far to be suggested: more than a third of key's length, with at least 2 typos allowed but
+// always fewer than key's length, so that a short key doesn't match every short name.
+func suggestionDistance(key, name string) int {
+	if name == "" {
+		return -1
+	}
+
+	if len(key) >= 2 && strings.HasPrefix(name, key) {
+		return min(1, len(name)-len(key))
+	}
+
+	length := len([]rune(key))
+	distance := editDistance(key, name)
+	if distance > min(max(2, length/3), length-1) {
+		return -1
+	}
+
+	return distance
+}
+
+// editDistance is the optimal string alignment distance between a and b, counted in runes: the
+// Levenshtein distance where swapping two adjacent runes is a single edit.
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+
+	distances := make([][]int, len(ar)+1)
+	for i := range distances {
+		distances[i] = make([]int, len(br)+1)
+		distances[i][0] = i
+	}
+	for j := range distances[0] {
+		distances[0][j] = j
+	}
+
+	for i := 1; i <= len(ar); i++ {
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+
+			distances[i][j] = min(distances[i-1][j]+1, distances[i][j-1]+1, distances[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ar[i-1] == br[j-2] && ar[i-2] == br[j-1] {
+				distances[i][j] = min(distances[i][j], distances[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return distances[len(ar)][len(br)]
+}
@@ -0,0 +1,94 @@
+package networks
+
+import (
+	"testing"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func networkIDs(networks []*registry.Network) []string {
+	ids := make([]string, len(networks))
+	for i, network := range networks {
+		ids[i] = network.ID
+	}
+
+	return ids
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("mainnet", "mainnet"))
+	assert.Equal(t, 1, editDistance("mainet", "mainnet"))
+	assert.Equal(t, 1, editDistance("sepoila", "sepolia"))
+	assert.Equal(t, 3, editDistance("", "eth"))
+	assert.Equal(t, 2, editDistance("héllo", "hallo!"))
+}
+
+func TestRegistry_Suggest(t *testing.T) {
+	r := loadEmbeddedRegistry(t)
+
+	tests := []struct {
+		key      string
+		n        int
+		expected []string
+	}{
+		{"arbitrim", 1, []string{"arbitrum-one"}},
+		{"sepoila", 1, []string{"sepolia"}},
+		{"mainet", 1, []string{"mainnet"}},
+		{"Etherum", 1, []string{"mainnet"}},
+		{"arbi", 3, []string{"arbitrum-nova", "arbitrum-one", "arbitrum-sepolia"}},
+		{"xyzzy", 3, []string{}},
+		{"x", 3, []string{}},
+		{"q", 3, []string{}},
+		{"mainnet", 0, nil},
+		{" ", 3, nil},
+	}
+
+	for _, test := range tests {
+		suggestions := r.Suggest(test.key, test.n)
+		if test.expected == nil {
+			assert.Nil(t, suggestions, "Suggest(%q, %d)", test.key, test.n)
+			continue
+		}
+
+		assert.Equal(t, test.expected, networkIDs(suggestions), "Suggest(%q, %d)", test.key, test.n)
+	}
+
+	assert.Len(t, r.Suggest("sepolia", 5), 5)
+}
+
+func TestRegistry_Lookup(t *testing.T) {
+	r := loadEmbeddedRegistry(t)
+
+	network, err := r.Lookup("ETH Mainnet")
+	require.NoError(t, err)
+	assert.Equal(t, "mainnet", network.ID)
+
+	network, err = r.Lookup("arbitrim")
+	assert.Nil(t, network)
+	assert.ErrorIs(t, err, ErrUnknownNetwork)
+	assert.EqualError(t, err, `unknown network "arbitrim", did you mean "arbitrum-one" or "arbitrum-sepolia"?`)
+
+	var unknown *UnknownNetworkError
+	require.ErrorAs(t, err, &unknown)
+	assert.Equal(t, "arbitrim", unknown.Key)
+
+	_, err = r.Lookup("xyzzy")
+	assert.EqualError(t, err, `unknown network "xyzzy"`)
+
+	_, err = r.Lookup("x")
+	assert.EqualError(t, err, `unknown network "x"`)
+
+	err = r.RegisterServiceOverride(&ServiceOverride{NetworkID: "mainet", Firehose: []string{"mainnet.internal:443"}})
+	assert.ErrorIs(t, err, ErrUnknownNetwork)
+	assert.ErrorContains(t, err, `did you mean "mainnet"`)
+}
+
+func TestUnknownNetworkError(t *testing.T) {
+	err := &UnknownNetworkError{Key: "eth", Suggestions: []*registry.Network{{ID: "mainnet"}, {ID: "sepolia"}, {ID: "holesky"}}}
+	assert.EqualError(t, err, `unknown network "eth", did you mean "mainnet", "sepolia" or "holesky"?`)
+
+	err.Suggestions = err.Suggestions[:1]
+	assert.EqualError(t, err, `unknown network "eth", did you mean "mainnet"?`)
+}
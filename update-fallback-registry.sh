@@ -41,6 +41,15 @@ new_file="fallback_TheGraphNetworkRegistry_${version}.json"
 mv "$tmp_file" "$new_file"
 echo "Downloaded and saved as $new_file"
 
+# Show the network changes brought by the new version for review
+echo ""
+go run ./cmd/firehose-networks diff "$old_file" "$new_file"
+echo ""
+
+# Report the overrides of overrides.go the new version made redundant or orphaned, to prune them
+go run ./cmd/firehose-networks overrides -stale -registry "$new_file" || echo "Review the stale overrides above in overrides.go"
+echo ""
+
 # Delete old file if different
 if [[ "$old_file" != "$new_file" ]]; then
   rm -f "$old_file"
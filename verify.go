@@ -0,0 +1,222 @@
+package networks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Verifier checks the integrity of a registry document before it's used, see [WithVerifier].
+type Verifier interface {
+	// Verify returns an error when document, loaded from location (a URL or a file path), must
+	// be rejected.
+	Verify(ctx context.Context, location string, document []byte) error
+}
+
+// VerifierFunc adapts a plain function to a [Verifier].
+type VerifierFunc func(ctx context.Context, location string, document []byte) error
+
+func (f VerifierFunc) Verify(ctx context.Context, location string, document []byte) error {
+	return f(ctx, location, document)
+}
+
+// WithVerifier verifies each registry document loaded by [LatestSource], [URLSource], [FileSource],
+// [EnvFileSource] and [ReaderSource] with verifier before using it, the option can be given many
+// times and all verifiers must accept the document. A rejected document fails the load of its
+// source like any other error: the initial load moves on to the next source of the chain while
+// refreshes keep the registry in use. Custom sources can apply the verifiers through
+// [VerifyDocument].
+//
+// The cache of [WithCacheDir] keeps documents as they were loaded and verifies them again when
+// read, with the location they were loaded from: verifiers fetching a detached signature from
+// there, like [Ed25519Verifier] does, reject the cache while that location is unreachable.
+func WithVerifier(verifier Verifier) Option {
+	return func(r *Registry) {
+		r.verifiers = append(r.verifiers, verifier)
+	}
+}
+
+// VerificationError is the failure of a [Verifier] to accept a registry document.
+type VerificationError struct {
+	// Location is the URL or file path the rejected document was loaded from.
+	Location string
+	Err      error
+}
+
+func (e *VerificationError) Error() string {
+	return fmt.Sprintf("verify %s: %s", e.Location, e.Err)
+}
+
+func (e *VerificationError) Unwrap() error {
+	return e.Err
+}
+
+type verifiersKey struct{}
+
+func withVerifiers(ctx context.Context, verifiers []Verifier) context.Context {
+	return context.WithValue(ctx, verifiersKey{}, verifiers)
+}
+
+type verifiedDocumentKey struct{}
+
+// verifiedDocument is the last document accepted by [VerifyDocument] along with its location,
+// recorded so the cache keeps the exact content that was verified.
+type verifiedDocument struct {
+	location string
+	content  []byte
+}
+
+func withVerifiedDocument(ctx context.Context) (context.Context, *verifiedDocument) {
+	document := &verifiedDocument{}
+	return context.WithValue(ctx, verifiedDocumentKey{}, document), document
+}
+
+// VerifyDocument verifies document, loaded from location, with the verifiers of the [Registry]
+// loading the source ctx is given to, see [WithVerifier]. It returns a [*VerificationError] when
+// the document is rejected and nil when no verifier is configured.
+func VerifyDocument(ctx context.Context, location string, document []byte) error {
+	verifiers, _ := ctx.Value(verifiersKey{}).([]Verifier)
+	for _, verifier := range verifiers {
+		if err := verifier.Verify(ctx, location, document); err != nil {
+			return &VerificationError{Location: location, Err: err}
+		}
+	}
+
+	if verified, ok := ctx.Value(verifiedDocumentKey{}).(*verifiedDocument); ok {
+		verified.location, verified.content = location, document
+	}
+
+	return nil
+}
+
+// SHA256Verifier accepts the registry documents whose SHA-256 digest, hex encoded, is one of
+// digests. It pins the registry to known documents, for example for reproducible deployments.
+func SHA256Verifier(digests ...string) Verifier {
+	pinned := make(map[string]bool, len(digests))
+	for _, digest := range digests {
+		pinned[strings.ToLower(strings.TrimSpace(digest))] = true
+	}
+
+	return VerifierFunc(func(_ context.Context, _ string, document []byte) error {
+		return checkSHA256(pinned, document)
+	})
+}
+
+// SHA256ManifestVerifier is like [SHA256Verifier] with the digests listed in the manifest file at
+// path, in the format of the `sha256sum` tool. The manifest is read on each verification so it can
+// be updated without restarting the process.
+func SHA256ManifestVerifier(path string) Verifier {
+	return VerifierFunc(func(_ context.Context, _ string, document []byte) error {
+		pinned, err := readSHA256Manifest(path)
+		if err != nil {
+			return err
+		}
+
+		return checkSHA256(pinned, document)
+	})
+}
+
+func checkSHA256(pinned map[string]bool, document []byte) error {
+	sum := sha256.Sum256(document)
+	digest := hex.EncodeToString(sum[:])
+	if !pinned[digest] {
+		return fmt.Errorf("sha256 digest %s is not pinned", digest)
+	}
+
+	return nil
+}
+
+func readSHA256Manifest(path string) (map[string]bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read sha256 manifest: %w", err)
+	}
+
+	pinned := make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		// Lines are `<digest>  <file name>`, the file name is not used
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		pinned[strings.ToLower(fields[0])] = true
+	}
+
+	return pinned, scanner.Err()
+}
+
+// Ed25519Verifier accepts the registry documents signed by one of publicKeys. The detached
+// signature of a document is read from its location with a `.sig` suffix, like
+// `https://mirror.internal/registry.json.sig`, either as the raw 64 bytes signature or base64
+// encoded.
+func Ed25519Verifier(publicKeys ...ed25519.PublicKey) Verifier {
+	return VerifierFunc(func(ctx context.Context, location string, document []byte) error {
+		signature, err := readSignature(ctx, location+".sig")
+		if err != nil {
+			return err
+		}
+
+		for _, publicKey := range publicKeys {
+			if ed25519.Verify(publicKey, document, signature) {
+				return nil
+			}
+		}
+
+		return errors.New("ed25519 signature doesn't match any public key")
+	})
+}
+
+func readSignature(ctx context.Context, location string) ([]byte, error) {
+	var content []byte
+	var err error
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		content, err = fetchSignature(ctx, location)
+	} else {
+		content, err = os.ReadFile(location)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read signature: %w", err)
+	}
+
+	if len(content) == ed25519.SignatureSize {
+		return content, nil
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("invalid signature %s", location)
+	}
+
+	return signature, nil
+}
+
+func fetchSignature(ctx context.Context, url string) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch %s: HTTP %d", url, response.StatusCode)
+	}
+
+	return io.ReadAll(response.Body)
+}
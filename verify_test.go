@@ -0,0 +1,149 @@
+package networks
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifiers(t *testing.T) {
+	document := []byte(testRegistryDocument)
+	tampered := []byte(strings.Replace(testRegistryDocument, "Mirrored Chain", "Tampered Chain", 1))
+
+	sum := sha256.Sum256(document)
+	digest := hex.EncodeToString(sum[:])
+
+	t.Run("sha256", func(t *testing.T) {
+		verifier := SHA256Verifier("0000", strings.ToUpper(digest))
+
+		assert.NoError(t, verifier.Verify(context.Background(), "registry.json", document))
+		assert.ErrorContains(t, verifier.Verify(context.Background(), "registry.json", tampered), "is not pinned")
+	})
+
+	t.Run("sha256 manifest", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "SHA256SUMS")
+		require.NoError(t, os.WriteFile(path, []byte("# pinned registries\n"+digest+"  TheGraphNetworksRegistry_v0_7_99.json\n"), 0o644))
+
+		verifier := SHA256ManifestVerifier(path)
+		assert.NoError(t, verifier.Verify(context.Background(), "registry.json", document))
+		assert.Error(t, verifier.Verify(context.Background(), "registry.json", tampered))
+
+		assert.ErrorContains(t, SHA256ManifestVerifier(path+".missing").Verify(context.Background(), "registry.json", document), "read sha256 manifest")
+	})
+
+	t.Run("ed25519", func(t *testing.T) {
+		publicKey, privateKey, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+		otherKey, _, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+
+		dir := t.TempDir()
+		raw := filepath.Join(dir, "raw.json")
+		require.NoError(t, os.WriteFile(raw+".sig", ed25519.Sign(privateKey, document), 0o644))
+		encoded := filepath.Join(dir, "encoded.json")
+		require.NoError(t, os.WriteFile(encoded+".sig", []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, document))+"\n"), 0o644))
+
+		verifier := Ed25519Verifier(otherKey, publicKey)
+		assert.NoError(t, verifier.Verify(context.Background(), raw, document))
+		assert.NoError(t, verifier.Verify(context.Background(), encoded, document))
+		assert.ErrorContains(t, verifier.Verify(context.Background(), raw, tampered), "doesn't match any public key")
+		assert.ErrorContains(t, Ed25519Verifier(otherKey).Verify(context.Background(), raw, document), "doesn't match any public key")
+		assert.ErrorContains(t, verifier.Verify(context.Background(), filepath.Join(dir, "unsigned.json"), document), "read signature")
+	})
+}
+
+func TestRegistry_WithVerifier(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	var content, signature atomic.Value
+	content.Store(testRegistryDocument)
+	signature.Store(ed25519.Sign(privateKey, []byte(testRegistryDocument)))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/registry.json":
+			w.Write([]byte(content.Load().(string)))
+		case "/registry.json.sig":
+			w.Write(signature.Load().([]byte))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("signed document is used", func(t *testing.T) {
+		r := New(WithSources(URLSource(server.URL+"/registry.json"), EmbeddedSource()), WithVerifier(Ed25519Verifier(publicKey)))
+
+		require.NoError(t, r.Load(context.Background()))
+		assert.True(t, r.Has("mirrored"))
+	})
+
+	t.Run("rejected document falls back to the next source", func(t *testing.T) {
+		source := URLSource(server.URL + "/registry.json")
+
+		var verificationErr *VerificationError
+		err := New(WithSources(source), WithVerifier(SHA256Verifier("0000"))).Load(context.Background())
+		require.ErrorAs(t, err, &verificationErr)
+		assert.Equal(t, server.URL+"/registry.json", verificationErr.Location)
+
+		r := New(WithSources(source, EmbeddedSource()), WithVerifier(SHA256Verifier("0000")))
+		require.NoError(t, r.Load(context.Background()))
+		assert.Equal(t, "embedded", r.Status().Source)
+	})
+
+	t.Run("reader document is verified", func(t *testing.T) {
+		var verificationErr *VerificationError
+		err := New(WithSources(ReaderSource("reader", strings.NewReader(testRegistryDocument))), WithVerifier(SHA256Verifier("0000"))).Load(context.Background())
+		require.ErrorAs(t, err, &verificationErr)
+		assert.Equal(t, "reader", verificationErr.Location)
+	})
+
+	t.Run("cached document is verified again", func(t *testing.T) {
+		sum := sha256.Sum256([]byte(testRegistryDocument))
+		pinned := SHA256Verifier(hex.EncodeToString(sum[:]))
+
+		dir := t.TempDir()
+		online := New(WithCacheDir(dir), WithSources(ReaderSource("reader", strings.NewReader(testRegistryDocument))), WithVerifier(pinned))
+		require.NoError(t, online.Load(context.Background()))
+
+		offline := New(WithCacheDir(dir), WithSources(failingSource, EmbeddedSource()), WithVerifier(pinned))
+		require.NoError(t, offline.Load(context.Background()))
+		assert.Equal(t, "cache", offline.Status().Source, "the cache holds the pinned document byte for byte")
+
+		offline = New(WithCacheDir(dir), WithSources(failingSource, EmbeddedSource()), WithVerifier(SHA256Verifier("0000")))
+		require.NoError(t, offline.Load(context.Background()))
+		assert.Equal(t, "embedded", offline.Status().Source)
+	})
+
+	t.Run("refresh keeps the registry when the document is rejected", func(t *testing.T) {
+		r := New(WithSources(URLSource(server.URL+"/registry.json")), WithVerifier(Ed25519Verifier(publicKey)))
+		require.NoError(t, r.Load(context.Background()))
+		initial := r.snapshot()
+
+		content.Store(strings.Replace(testRegistryDocument, `"0.7.99"`, `"0.7.100"`, 1))
+		defer content.Store(testRegistryDocument)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		r.ScheduleUpdateLatestRegistry(ctx, time.Millisecond)
+
+		assert.Eventually(t, func() bool { return r.Status().LastError != nil }, 5*time.Second, time.Millisecond)
+		assert.ErrorContains(t, r.Status().LastError, fmt.Sprintf("verify %s/registry.json", server.URL))
+		assert.Same(t, initial, r.snapshot())
+	})
+}
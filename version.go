@@ -0,0 +1,192 @@
+package networks
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+)
+
+// WithVersion constrains the version of the registry documents the registry accepts, both on
+// load and on refresh, a document from a source not satisfying it fails that source like any
+// other error. The constraint is either an exact version like `0.7.34` or a range made of
+// comparisons separated by commas or spaces, like `>=0.7.20, <0.8.0`, supported operators being
+// `=`, `>`, `>=`, `<` and `<=`.
+//
+// When the constraint pins an exact version, [LatestSource] in the chain is replaced by
+// [VersionSource] loading that version, so lookups resolve identically across runs. The
+// [EmbeddedSource] is only used when its version satisfies the constraint.
+func WithVersion(constraint string) Option {
+	return func(r *Registry) {
+		versionConstraint, err := parseVersionConstraint(constraint)
+		if err != nil {
+			r.configErr = errors.Join(r.configErr, err)
+			return
+		}
+
+		r.versionConstraint = versionConstraint
+	}
+}
+
+// VersionSource loads the exact registry version from The Graph, like `0.7.34`, using the
+// GitHub mirror when the main host fails.
+func VersionSource(version string) Source {
+	return &urlSource{
+		name: "v" + version,
+		urls: []string{registry.GetExactVersionUrl(version), registry.GetExactVersionFallbackUrl(version)},
+	}
+}
+
+// semver is a `major.minor.patch` version, pre-release and build metadata are not supported as
+// the registry doesn't use them.
+type semver struct {
+	major, minor, patch int
+}
+
+func parseSemver(in string) (semver, error) {
+	parts := strings.Split(strings.TrimPrefix(in, "v"), ".")
+	if len(parts) != 3 {
+		return semver{}, fmt.Errorf("invalid version %q, expected major.minor.patch", in)
+	}
+
+	var numbers [3]int
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return semver{}, fmt.Errorf("invalid version %q, expected major.minor.patch", in)
+		}
+
+		numbers[i] = number
+	}
+
+	return semver{numbers[0], numbers[1], numbers[2]}, nil
+}
+
+func (v semver) compare(other semver) int {
+	switch {
+	case v.major != other.major:
+		return v.major - other.major
+	case v.minor != other.minor:
+		return v.minor - other.minor
+	default:
+		return v.patch - other.patch
+	}
+}
+
+func (v semver) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+}
+
+type versionBound struct {
+	operator string
+	version  semver
+}
+
+func (b versionBound) matches(v semver) bool {
+	comparison := v.compare(b.version)
+	switch b.operator {
+	case ">":
+		return comparison > 0
+	case ">=":
+		return comparison >= 0
+	case "<":
+		return comparison < 0
+	case "<=":
+		return comparison <= 0
+	default:
+		return comparison == 0
+	}
+}
+
+type versionConstraint struct {
+	raw    string
+	bounds []versionBound
+}
+
+var (
+	versionBoundRegex    = regexp.MustCompile(`^(>=|<=|>|<|=)?(\S+)$`)
+	versionOperatorRegex = regexp.MustCompile(`(>=|<=|>|<|=)\s+`)
+)
+
+func parseVersionConstraint(in string) (*versionConstraint, error) {
+	constraint := &versionConstraint{raw: in}
+
+	// Operators may be separated from their version by spaces, glue them back before splitting
+	normalized := versionOperatorRegex.ReplaceAllString(in, "$1")
+	for _, field := range strings.FieldsFunc(normalized, func(r rune) bool { return r == ',' || r == ' ' }) {
+		match := versionBoundRegex.FindStringSubmatch(field)
+		if match == nil {
+			return nil, fmt.Errorf("invalid version constraint %q", in)
+		}
+
+		version, err := parseSemver(match[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %w", in, err)
+		}
+
+		constraint.bounds = append(constraint.bounds, versionBound{operator: match[1], version: version})
+	}
+
+	if len(constraint.bounds) == 0 {
+		return nil, fmt.Errorf("invalid version constraint %q: empty", in)
+	}
+
+	return constraint, nil
+}
+
+// exact returns the version pinned by the constraint, if any.
+func (c *versionConstraint) exact() (semver, bool) {
+	if len(c.bounds) == 1 && (c.bounds[0].operator == "" || c.bounds[0].operator == "=") {
+		return c.bounds[0].version, true
+	}
+
+	return semver{}, false
+}
+
+func (c *versionConstraint) matches(v semver) bool {
+	for _, bound := range c.bounds {
+		if !bound.matches(v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+var schemaVersionRegex = regexp.MustCompile(`_v(\d+)_(\d+)\.json$`)
+
+// checkVersion rejects registry documents not satisfying the version constraint of the registry
+// or whose `$schema` is newer than the one understood by the registry library. Following semver,
+// a minor bump of a 0.x schema is considered a major one.
+func (r *Registry) checkVersion(native *registry.NetworksRegistry) error {
+	if match := schemaVersionRegex.FindStringSubmatch(native.Schema); match != nil {
+		library, err := parseSemver(registry.Version)
+		if err != nil {
+			return fmt.Errorf("invalid registry library version: %w", err)
+		}
+
+		major, _ := strconv.Atoi(match[1])
+		minor, _ := strconv.Atoi(match[2])
+		if major > library.major || (major == 0 && library.major == 0 && minor > library.minor) {
+			return fmt.Errorf("registry schema v%d.%d is newer than v%d.%d supported by this module", major, minor, library.major, library.minor)
+		}
+	}
+
+	if r.versionConstraint == nil {
+		return nil
+	}
+
+	version, err := parseSemver(native.Version)
+	if err != nil {
+		return fmt.Errorf("registry version: %w", err)
+	}
+
+	if !r.versionConstraint.matches(version) {
+		return fmt.Errorf("registry version %s doesn't satisfy constraint %q", version, r.versionConstraint.raw)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package networks
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	registry "github.com/pinax-network/graph-networks-libs/packages/golang/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersionConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		matching   []string
+		rejected   []string
+		exact      string
+	}{
+		{"0.7.34", []string{"0.7.34", "v0.7.34"}, []string{"0.7.33", "0.7.35", "0.8.34"}, "0.7.34"},
+		{"=0.7.34", []string{"0.7.34"}, []string{"0.7.35"}, "0.7.34"},
+		{">=0.7.20, <0.8.0", []string{"0.7.20", "0.7.99"}, []string{"0.7.19", "0.8.0"}, ""},
+		{"> 0.7.20 <= 0.7.30", []string{"0.7.21", "0.7.30"}, []string{"0.7.20", "0.7.31"}, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.constraint, func(t *testing.T) {
+			constraint, err := parseVersionConstraint(test.constraint)
+			require.NoError(t, err)
+
+			for _, in := range test.matching {
+				version, err := parseSemver(in)
+				require.NoError(t, err)
+				assert.True(t, constraint.matches(version), in)
+			}
+
+			for _, in := range test.rejected {
+				version, err := parseSemver(in)
+				require.NoError(t, err)
+				assert.False(t, constraint.matches(version), in)
+			}
+
+			exact, ok := constraint.exact()
+			assert.Equal(t, test.exact != "", ok)
+			if ok {
+				assert.Equal(t, test.exact, exact.String())
+			}
+		})
+	}
+
+	for _, invalid := range []string{"", "latest", ">=0.7", "~0.7.1", "0.7.x"} {
+		_, err := parseVersionConstraint(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestRegistry_WithVersion(t *testing.T) {
+	mirror := func(document string) Source {
+		return ReaderSource("mirror", strings.NewReader(document))
+	}
+
+	t.Run("exact version replaces the latest source", func(t *testing.T) {
+		r := New(WithVersion("0.7.34"))
+
+		assert.Equal(t, []string{"v0.7.34", "embedded"}, sourceNames(r.sources))
+		assert.Equal(t, registry.GetExactVersionUrl("0.7.34"), r.sources[0].(*urlSource).urls[0])
+	})
+
+	t.Run("documents outside the range fall back to the next source", func(t *testing.T) {
+		r := New(WithSources(mirror(testRegistryDocument), EmbeddedSource()), WithVersion(">=0.7.0, <0.7.50"))
+		require.NoError(t, r.Load(context.Background()))
+		assert.Equal(t, "embedded", r.Status().Source)
+
+		r = New(WithSources(mirror(testRegistryDocument), EmbeddedSource()), WithVersion(">=0.7.50"))
+		require.NoError(t, r.Load(context.Background()))
+		assert.Equal(t, "mirror", r.Status().Source)
+	})
+
+	t.Run("embedded registry is rejected when outside the range", func(t *testing.T) {
+		err := New(WithSources(EmbeddedSource()), WithVersion("0.7.99")).Load(context.Background())
+		assert.ErrorContains(t, err, `registry version 0.7.34 doesn't satisfy constraint "0.7.99"`)
+	})
+
+	t.Run("invalid constraint fails loading", func(t *testing.T) {
+		r := New(WithSources(EmbeddedSource()), WithVersion("latest"))
+
+		err := r.Load(context.Background())
+		assert.ErrorContains(t, err, `invalid registry configuration: invalid version constraint "latest"`)
+
+		assert.False(t, r.Has("mainnet"))
+		status := r.Status()
+		assert.False(t, status.Loaded)
+		assert.False(t, status.Retrying, "an invalid configuration is not retried")
+		assert.ErrorContains(t, status.LastError, `invalid registry configuration: invalid version constraint "latest"`)
+	})
+
+	t.Run("newer schema is rejected", func(t *testing.T) {
+		newer := strings.Replace(testRegistryDocument, "Schema_v0_7.json", "Schema_v0_8.json", 1)
+		err := New(WithSources(mirror(newer))).Load(context.Background())
+		assert.ErrorContains(t, err, "registry schema v0.8 is newer than v0.7 supported by this module")
+
+		older := strings.Replace(testRegistryDocument, "Schema_v0_7.json", "Schema_v0_6.json", 1)
+		assert.NoError(t, New(WithSources(mirror(older))).Load(context.Background()))
+	})
+}